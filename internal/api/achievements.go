@@ -0,0 +1,52 @@
+/*
+Package api
+File: achievements.go
+Description:
+    Exposes achievements and milestone rewards.
+
+    Key Responsibilities:
+    - Achievements Endpoint: Every achievement, the player's unlocks and cosmetics.
+    - Notifications: Pushes new unlocks to the player as "achievement_unlocked" WebSocket messages.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/everforgeworks/galaxies-burn-rate/internal/game"
+)
+
+// AchievementsResponse lists every achievement alongside the cosmetics the player has unlocked.
+type AchievementsResponse struct {
+	Achievements []game.AchievementStatus `json:"achievements"`
+	Cosmetics    []string                 `json:"cosmetics"`
+}
+
+// HandleGetAchievements returns every achievement and whether the player has unlocked it.
+func HandleGetAchievements(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	cosmetics := p.Cosmetics
+	if cosmetics == nil {
+		cosmetics = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AchievementsResponse{Achievements: game.ListAchievements(p), Cosmetics: cosmetics})
+}
+
+// notifyAchievements evaluates the achievements of an event and pushes every unlock to the player.
+// Note: Caller must hold game.DataLock
+func notifyAchievements(hub *Hub, p *game.Player, event string) {
+	for _, unlock := range game.EvaluateAchievements(p, event) {
+		hub.SendTo([]string{p.ID}, Message{Type: "achievement_unlocked", Payload: unlock, Sender: "system"})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Package api
+File: bank.go
+Description:
+    Exposes loans, emergency tows and bankruptcy.
+
+    Key Responsibilities:
+    - Bank Endpoints: Debt status, loans and repayments (only at planets with a bank).
+    - Rescue Endpoint: Towing a stranded active ship to the nearest depot.
+    - Bankruptcy Endpoint: Resetting an insolvent player.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/everforgeworks/galaxies-burn-rate/internal/game"
+)
+
+// LoanRequest selects the amount to borrow or repay. A repayment of 0 pays back as much as possible.
+type LoanRequest struct {
+	Amount int `json:"amount"`
+}
+
+// TowResponse is the player status after an emergency tow, plus the tow details.
+type TowResponse struct {
+	PlayerStatus
+	Tow game.TowResult `json:"tow"`
+}
+
+// HandleGetBank returns the player's debt, loan limit and rescue options.
+func HandleGetBank(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.BuildBankStatus(p))
+}
+
+// HandleTakeLoan borrows credits at the local bank.
+func HandleTakeLoan(w http.ResponseWriter, r *http.Request) {
+	var req LoanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.TakeLoan(p, req.Amount); err != nil {
+		writeBankError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleRepayLoan pays back debt at the local bank.
+func HandleRepayLoan(w http.ResponseWriter, r *http.Request) {
+	var req LoanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := game.RepayLoan(p, req.Amount); err != nil {
+		writeBankError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleTow rescues the stranded active ship. The fee the wallet can't cover becomes debt.
+func HandleTow(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	tow, err := game.TowShip(p, p.ActiveShip())
+	if err != nil {
+		writeBankError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TowResponse{PlayerStatus: statusOf(p), Tow: tow})
+}
+
+// HandleBankruptcy resets an insolvent player to a fresh start.
+func HandleBankruptcy(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.DeclareBankruptcy(p); err != nil {
+		writeBankError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// writeBankError maps game banking errors to HTTP responses.
+func writeBankError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrNoBank):
+		http.Error(w, "No bank at this location", http.StatusForbidden)
+	case errors.Is(err, game.ErrInvalidAmount):
+		http.Error(w, "Amount must be positive", http.StatusBadRequest)
+	case errors.Is(err, game.ErrLoanLimit):
+		http.Error(w, "Loan would exceed your debt limit", http.StatusConflict)
+	case errors.Is(err, game.ErrNoDebt):
+		http.Error(w, "You have no debt", http.StatusConflict)
+	case errors.Is(err, game.ErrInsufficientCredits):
+		http.Error(w, "Insufficient Credits", http.StatusPaymentRequired)
+	case errors.Is(err, game.ErrNotStranded):
+		http.Error(w, "Ship is not stranded", http.StatusConflict)
+	case errors.Is(err, game.ErrNoFuelDepot):
+		http.Error(w, "No depot to tow to", http.StatusNotFound)
+	case errors.Is(err, game.ErrSolvent):
+		http.Error(w, "Your net worth still covers your debt", http.StatusConflict)
+	default:
+		http.Error(w, "Invalid bank request", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,382 @@
+/*
+Package api
+File: corporations.go
+Description:
+    Exposes player corporations.
+
+    Key Responsibilities:
+    - Membership Endpoints: Listing, founding, joining (by invite) and leaving corporations.
+    - Management Endpoints: Invites, roles, removing members and the tax rate.
+    - Treasury Endpoints: Deposits and (officer) withdrawals.
+    - Chat Endpoint: Corp-only messages, delivered live over the WebSocket hub.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/everforgeworks/galaxies-burn-rate/internal/game"
+)
+
+// CreateCorpRequest founds a corporation.
+type CreateCorpRequest struct {
+	Name string `json:"name"`
+}
+
+// JoinCorpRequest selects the corporation to join.
+type JoinCorpRequest struct {
+	CorpID string `json:"corp_id"`
+}
+
+// CorpMemberRequest selects a member (and the new role when changing roles).
+type CorpMemberRequest struct {
+	PlayerID string `json:"player_id"`
+	Role     string `json:"role"`
+}
+
+// CorpTaxRequest sets the percent of member payouts paid into the treasury.
+type CorpTaxRequest struct {
+	TaxRate int `json:"tax_rate"`
+}
+
+// CorpTreasuryRequest selects the amount to deposit or withdraw.
+type CorpTreasuryRequest struct {
+	Amount int `json:"amount"`
+}
+
+// CorpChatRequest is a message to the other members.
+type CorpChatRequest struct {
+	Text string `json:"text"`
+}
+
+// HandleListCorps returns every corporation (name, size, tax and treasury) and whether it invited the player.
+func HandleListCorps(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.ListCorporations(p))
+}
+
+// HandleGetCorp returns the player's corporation with members, stats and chat history.
+func HandleGetCorp(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	writeCorpStatus(w, p)
+}
+
+// HandleCreateCorp founds a corporation with the player as founder.
+func HandleCreateCorp(w http.ResponseWriter, r *http.Request) {
+	var req CreateCorpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := game.CreateCorporation(p, req.Name); err != nil {
+		writeCorpError(w, err)
+		return
+	}
+	writeCorpStatus(w, p)
+}
+
+// HandleJoinCorp adds the player to a corporation that invited them as a member.
+func HandleJoinCorp(w http.ResponseWriter, r *http.Request) {
+	var req JoinCorpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := game.JoinCorporation(p, req.CorpID); err != nil {
+		writeCorpError(w, err)
+		return
+	}
+	writeCorpStatus(w, p)
+}
+
+// HandleInviteCorpMember invites a player to join (officers and the founder).
+// A connected invitee receives a "corp_invite" WebSocket message.
+func HandleInviteCorpMember(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	var req CorpMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	corp, err := game.InviteMember(p, req.PlayerID)
+	if err != nil {
+		writeCorpError(w, err)
+		return
+	}
+	invite := game.CorpSummary{ID: corp.ID, Name: corp.Name, Members: len(corp.Members), TaxRate: corp.TaxRate, Treasury: corp.Treasury, Invited: true}
+	hub.SendTo([]string{req.PlayerID}, Message{Type: "corp_invite", Payload: invite, Sender: p.ID})
+	writeCorpStatus(w, p)
+}
+
+// HandleRevokeCorpInvite withdraws a pending invite (officers and the founder).
+func HandleRevokeCorpInvite(w http.ResponseWriter, r *http.Request) {
+	var req CorpMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.RevokeInvite(p, req.PlayerID); err != nil {
+		writeCorpError(w, err)
+		return
+	}
+	writeCorpStatus(w, p)
+}
+
+// HandleLeaveCorp removes the player from their corporation.
+func HandleLeaveCorp(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.LeaveCorporation(p); err != nil {
+		writeCorpError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleSetCorpRole changes the role of a member (founder only).
+func HandleSetCorpRole(w http.ResponseWriter, r *http.Request) {
+	var req CorpMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.SetCorpRole(p, req.PlayerID, req.Role); err != nil {
+		writeCorpError(w, err)
+		return
+	}
+	writeCorpStatus(w, p)
+}
+
+// HandleKickCorpMember removes a lower-ranked member from the corporation.
+func HandleKickCorpMember(w http.ResponseWriter, r *http.Request) {
+	var req CorpMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.KickMember(p, req.PlayerID); err != nil {
+		writeCorpError(w, err)
+		return
+	}
+	writeCorpStatus(w, p)
+}
+
+// HandleSetCorpTax changes the tax on member payouts (founder only).
+func HandleSetCorpTax(w http.ResponseWriter, r *http.Request) {
+	var req CorpTaxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.SetCorpTax(p, req.TaxRate); err != nil {
+		writeCorpError(w, err)
+		return
+	}
+	writeCorpStatus(w, p)
+}
+
+// HandleCorpDeposit pays credits into the treasury.
+func HandleCorpDeposit(w http.ResponseWriter, r *http.Request) {
+	handleCorpTreasury(w, r, game.DepositTreasury)
+}
+
+// HandleCorpWithdraw takes credits out of the treasury (officers and the founder).
+func HandleCorpWithdraw(w http.ResponseWriter, r *http.Request) {
+	handleCorpTreasury(w, r, game.WithdrawTreasury)
+}
+
+// handleCorpTreasury runs a treasury operation and returns the player status.
+func handleCorpTreasury(w http.ResponseWriter, r *http.Request, op func(*game.Player, int) error) {
+	var req CorpTreasuryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := op(p, req.Amount); err != nil {
+		writeCorpError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleCorpChat sends a message to every member of the player's corporation.
+// Connected members receive it as a "corp_chat" WebSocket message.
+func HandleCorpChat(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	var req CorpChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	msg, members, err := game.PostCorpChat(p, req.Text)
+	if err != nil {
+		writeCorpError(w, err)
+		return
+	}
+	hub.SendTo(members, Message{Type: "corp_chat", Payload: msg, Sender: p.ID})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(msg)
+}
+
+// writeCorpStatus responds with the player's corporation.
+// Note: Caller must hold game.DataLock
+func writeCorpStatus(w http.ResponseWriter, p *game.Player) {
+	corp := game.GetCorporation(p)
+	if corp == nil {
+		writeCorpError(w, game.ErrNotInCorp)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.BuildCorpStatus(corp))
+}
+
+// writeCorpError maps game corporation errors to HTTP responses.
+func writeCorpError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrCorpNotFound):
+		http.Error(w, "Corporation not found", http.StatusNotFound)
+	case errors.Is(err, game.ErrNotInCorp):
+		http.Error(w, "Not a member of a corporation", http.StatusNotFound)
+	case errors.Is(err, game.ErrPlayerNotFound):
+		http.Error(w, "Player not found", http.StatusNotFound)
+	case errors.Is(err, game.ErrNotInvited):
+		http.Error(w, "Not invited to this corporation", http.StatusForbidden)
+	case errors.Is(err, game.ErrAlreadyInCorp):
+		http.Error(w, "Already a member of a corporation", http.StatusConflict)
+	case errors.Is(err, game.ErrCorpNameTaken):
+		http.Error(w, "Corporation name already taken", http.StatusConflict)
+	case errors.Is(err, game.ErrCorpFull):
+		http.Error(w, "Corporation is full", http.StatusConflict)
+	case errors.Is(err, game.ErrCorpRole):
+		http.Error(w, "Corporation role too low", http.StatusForbidden)
+	case errors.Is(err, game.ErrInvalidCorpName):
+		http.Error(w, "Invalid corporation name", http.StatusBadRequest)
+	case errors.Is(err, game.ErrInvalidRole):
+		http.Error(w, "Invalid role", http.StatusBadRequest)
+	case errors.Is(err, game.ErrInvalidTax):
+		http.Error(w, "Tax rate out of range", http.StatusBadRequest)
+	case errors.Is(err, game.ErrInvalidMessage):
+		http.Error(w, "Message must not be empty or too long", http.StatusBadRequest)
+	case errors.Is(err, game.ErrInvalidAmount):
+		http.Error(w, "Amount must be positive", http.StatusBadRequest)
+	case errors.Is(err, game.ErrInsufficientCredits):
+		http.Error(w, "Insufficient Credits", http.StatusPaymentRequired)
+	case errors.Is(err, game.ErrTransfersLocked):
+		http.Error(w, "Not enough lifetime earnings to send value to other players", http.StatusForbidden)
+	case errors.Is(err, game.ErrTransferCap):
+		http.Error(w, "Daily transfer limit reached", http.StatusTooManyRequests)
+	case errors.Is(err, game.ErrInDebt):
+		http.Error(w, "Repay your debt before sending value to other players", http.StatusConflict)
+	default:
+		http.Error(w, "Invalid Request", http.StatusBadRequest)
+	}
+}
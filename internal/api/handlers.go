@@ -16,7 +16,10 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	// Import the game logic package we created
 	"github.com/everforgeworks/galaxies-burn-rate/internal/game"
@@ -29,19 +32,51 @@ type TravelRequest struct {
 	DestinationKey string `json:"destination_key"`
 }
 
+// ContractRequest selects a contract. Quantity takes only part of a cargo contract (0 = all of it).
 type ContractRequest struct {
 	ContractID string `json:"contract_id"`
+	Quantity   int    `json:"quantity"`
+}
+
+// PostContractRequest puts a cargo contract from the hold on the local job board.
+type PostContractRequest struct {
+	ContractID string `json:"contract_id"`
+	Payout     int    `json:"payout"` // Offered to the hauler, held in escrow
 }
 
 type BuyModuleRequest struct {
 	ModuleKey string `json:"module_key"`
 }
 
+// RefuelRequest selects how much fuel to buy. Leave both fields empty to fill the tank.
+type RefuelRequest struct {
+	Amount int64 `json:"amount"` // Exact fuel units to buy
+	Budget int   `json:"budget"` // Spend at most this many Credits
+}
+
+// SellModuleRequest selects a module to sell. Stored = true sells from storage instead of a slot.
+type SellModuleRequest struct {
+	ModuleKey string `json:"module_key"`
+	Stored    bool   `json:"stored"`
+}
+
+type SellModuleResponse struct {
+	Refund int          `json:"refund"`
+	Player PlayerStatus `json:"player"`
+}
+
+// TravelResponse is the player's status after a flight, plus what happened on the way.
+type TravelResponse struct {
+	PlayerStatus
+	Trip game.TravelResult `json:"trip"`
+}
+
 type TravelQuoteResponse struct {
-	Distance  int64 `json:"distance"`
-	FuelCost  int64 `json:"fuel_cost"`
-	CanAfford bool  `json:"can_afford"`
-	BurnRate  int64 `json:"burn_rate"`
+	Distance        int64           `json:"distance"`
+	FuelCost        int64           `json:"fuel_cost"`
+	CanAfford       bool            `json:"can_afford"`
+	BurnRate        int64           `json:"burn_rate"`
+	DestinationFuel game.FuelMarket `json:"destination_fuel"` // Can we refuel on arrival, and at what price?
 }
 
 // HandleGetPlanets returns the static list of all planets.
@@ -53,13 +88,18 @@ func HandleGetPlanets(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(game.CurrentUniverse.Planets)
 }
 
-// HandleGetShip returns the current state of the player's ship.
+// HandleGetShip returns the player's wallet and fleet along with the active ship.
 func HandleGetShip(w http.ResponseWriter, r *http.Request) {
 	game.DataLock.RLock()
 	defer game.DataLock.RUnlock()
 
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(game.PlayerShip)
+	json.NewEncoder(w).Encode(statusOf(p))
 }
 
 // HandleGetContracts returns jobs available at the ship's CURRENT location.
@@ -67,29 +107,94 @@ func HandleGetContracts(w http.ResponseWriter, r *http.Request) {
 	game.DataLock.RLock()
 	defer game.DataLock.RUnlock()
 
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	ship := p.ActiveShip()
+
 	w.Header().Set("Content-Type", "application/json")
 	// Only show contracts for the planet the ship is currently on
-	location := game.PlayerShip.LocationKey
-	json.NewEncoder(w).Encode(game.AvailableContracts[location])
+	json.NewEncoder(w).Encode(game.VisibleContracts(p, ship.LocationKey))
 }
 
 // HandleGetModules returns upgrade modules available for purchase.
-// Only returns data if the player is at the central hub ("planet_prime").
+// Each entry describes whether it can be bought and why not, so the client can render the upgrade tree.
+// Only returns data if the player is docked at a planet with a shipyard.
 func HandleGetModules(w http.ResponseWriter, r *http.Request) {
 	game.DataLock.RLock()
 	defer game.DataLock.RUnlock()
 
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	ship := p.ActiveShip()
+
 	w.Header().Set("Content-Type", "application/json")
-	if game.PlayerShip.LocationKey != "planet_prime" {
-		json.NewEncoder(w).Encode([]game.ShipModule{})
+	if !game.HasService(ship.LocationKey, game.ServiceShipyard) {
+		json.NewEncoder(w).Encode([]game.ModuleOffer{})
 		return
 	}
-	json.NewEncoder(w).Encode(game.CurrentUniverse.ShipModules)
+	json.NewEncoder(w).Encode(game.OfferModules(p))
+}
+
+// HandleGetFuel returns the live fuel price and depot stock of every planet.
+func HandleGetFuel(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	markets := []game.FuelMarket{}
+	for _, p := range game.CurrentUniverse.Planets {
+		markets = append(markets, game.GetFuelMarket(p.Key))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(markets)
+}
+
+// HandleGetEvents returns the galactic events currently in effect.
+func HandleGetEvents(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	events := game.ActiveEvents
+	if events == nil {
+		events = []game.ActiveEvent{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// HandleGetTraders returns the simulated NPC traders and what they are carrying.
+func HandleGetTraders(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.Traders)
+}
+
+// HandleGetFactions returns every faction with its territory and the player's standing.
+func HandleGetFactions(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.BuildFactionStatus(p))
 }
 
 // HandleAcceptContract moves a contract from the Planet Board to the Ship.
-// Triggers Market Scarcity (Source Heat).
-func HandleAcceptContract(w http.ResponseWriter, r *http.Request) {
+// Triggers Market Scarcity (Source Heat) and evaluates "accept" achievements.
+func HandleAcceptContract(hub *Hub, w http.ResponseWriter, r *http.Request) {
 	var req ContractRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Bad Request", http.StatusBadRequest)
@@ -99,147 +204,216 @@ func HandleAcceptContract(w http.ResponseWriter, r *http.Request) {
 	game.DataLock.Lock() // Write Lock (Exclusive access required)
 	defer game.DataLock.Unlock()
 
-	location := game.PlayerShip.LocationKey
-	board := game.AvailableContracts[location]
-
-	// 1. Find the contract
-	var target game.Contract
-	foundIdx := -1
-	for i, c := range board {
-		if c.ID == req.ContractID {
-			target = c
-			foundIdx = i
-			break
-		}
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
 	}
 
-	if foundIdx == -1 {
-		http.Error(w, "Contract not found", http.StatusNotFound)
+	// A partial acceptance leaves the remainder on the board under a new ID (contracts.go)
+	if _, err := game.AcceptPartialContract(p, p.ActiveShip(), req.ContractID, req.Quantity); err != nil {
+		writeOperationError(w, err)
 		return
 	}
+	notifyAchievements(hub, p, game.AchievementOnAccept) // Defined in achievements.go
 
-	// 2. Validate Ship Capacity
-	// We must count currently loaded items to ensure we don't overfill.
-	currentCargo, currentPass := 0, 0
-	for _, ac := range game.PlayerShip.ActiveContracts {
-		if ac.Type == "cargo" {
-			currentCargo += ac.Quantity
-		} else {
-			currentPass += ac.Quantity
-		}
-	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
 
-	if target.Type == "cargo" && currentCargo+target.Quantity > game.PlayerShip.CargoCapacity {
-		http.Error(w, "Insufficient Cargo Space", http.StatusConflict)
+// HandleTravel moves the ship between planets.
+// Consumes fuel and triggers Market Saturation (Dest Heat) if contracts are delivered.
+// Evaluates "travel" achievements on arrival.
+func HandleTravel(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	var req TravelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid Request", http.StatusBadRequest)
 		return
 	}
-	if target.Type == "passenger" && currentPass+target.Quantity > game.PlayerShip.PassengerSlots {
-		http.Error(w, "Insufficient Passenger Slots", http.StatusConflict)
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
 		return
 	}
 
-	// 3. Transfer Contract
-	// Add to ship...
-	game.PlayerShip.ActiveContracts = append(game.PlayerShip.ActiveContracts, target)
-	// ...remove from planet
-	game.AvailableContracts[location] = append(board[:foundIdx], board[foundIdx+1:]...)
-
-	// 4. Update Market Economy
-	// Accepting a contract makes the good scarcer at the origin.
-	game.Market.RecordAcceptance(target.OriginKey, target.ItemKey, target.Quantity)
+	// Fuel burn, deliveries and payouts are handled by the game package (travel.go)
+	trip, err := game.TravelShip(p, p.ActiveShip(), req.DestinationKey)
+	if err != nil {
+		writeOperationError(w, err)
+		return
+	}
+	notifyAchievements(hub, p, game.AchievementOnTravel) // Defined in achievements.go
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(game.PlayerShip)
+	json.NewEncoder(w).Encode(TravelResponse{PlayerStatus: statusOf(p), Trip: trip})
 }
 
-// HandleTravel moves the ship between planets.
-// Consumes fuel and triggers Market Saturation (Dest Heat) if contracts are delivered.
-func HandleTravel(w http.ResponseWriter, r *http.Request) {
-	var req TravelRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid Request", http.StatusBadRequest)
+// HandleRefuel buys fuel for a credit fee.
+// Body (optional): RefuelRequest. An empty body fills the tank to max capacity.
+func HandleRefuel(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRefuelRequest(w, r)
+	if !ok {
 		return
 	}
 
 	game.DataLock.Lock()
 	defer game.DataLock.Unlock()
 
-	dest := game.GetPlanet(req.DestinationKey)
-	current := game.GetPlanet(game.PlayerShip.LocationKey)
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
 
-	if dest == nil {
-		http.Error(w, "Destination invalid", http.StatusNotFound)
+	ship := p.ActiveShip()
+
+	quote, err := game.PlanRefuel(p, ship, req.Amount, req.Budget)
+	if err != nil {
+		writeRefuelError(w, err)
 		return
 	}
 
-	// 1. Calculate Costs (Physics)
-	dist := game.CalculateDistance(current.Coordinates, dest.Coordinates)
-	currentBurn := game.CalculateCurrentBurn() // Uses the new additive mass logic
-	fuelNeeded := dist * currentBurn
+	if err := game.BuyFuel(p, ship, quote); err != nil {
+		http.Error(w, "Insufficient credits", http.StatusForbidden)
+		return
+	}
 
-	if game.PlayerShip.Fuel < fuelNeeded {
-		http.Error(w, "Insufficient Fuel for current mass", http.StatusPaymentRequired)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleRefuelQuote prices a refuel without buying anything.
+// Lets the UI show the cost and the resulting burn rate so players can choose to fly light.
+func HandleRefuelQuote(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRefuelRequest(w, r)
+	if !ok {
 		return
 	}
 
-	// 2. Move Ship
-	game.PlayerShip.Fuel -= fuelNeeded
-	game.PlayerShip.LocationKey = dest.Key
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
 
-	// 3. Process Deliveries
-	// Check if any onboard contracts are meant for this destination.
-	remainingContracts := []game.Contract{}
-	payoutTotal := 0
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
 
-	for _, c := range game.PlayerShip.ActiveContracts {
-		if c.DestinationKey == game.PlayerShip.LocationKey {
-			// Contract Completed!
-			payoutTotal += c.Payout
+	quote, err := game.PlanRefuel(p, p.ActiveShip(), req.Amount, req.Budget)
+	if err != nil {
+		writeRefuelError(w, err)
+		return
+	}
 
-			// Economy Update: Flooding the market at destination
-			game.Market.RecordDelivery(c.DestinationKey, c.ItemKey, c.Quantity)
-		} else {
-			// Contract stays on board
-			remainingContracts = append(remainingContracts, c)
-		}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+// decodeRefuelRequest parses the optional refuel body.
+// Returns false (after writing the error) if the body is malformed.
+func decodeRefuelRequest(w http.ResponseWriter, r *http.Request) (RefuelRequest, bool) {
+	var req RefuelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+// writeRefuelError maps game refuel errors to HTTP responses.
+func writeRefuelError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrTankFull):
+		http.Error(w, "Tank is already full", http.StatusBadRequest)
+	case errors.Is(err, game.ErrExceedsTank):
+		http.Error(w, "Requested fuel exceeds tank capacity", http.StatusConflict)
+	case errors.Is(err, game.ErrBudgetTooSmall):
+		http.Error(w, "Budget too small to buy any fuel", http.StatusBadRequest)
+	case errors.Is(err, game.ErrNoFuelDepot):
+		http.Error(w, "No fuel depot at this location", http.StatusForbidden)
+	case errors.Is(err, game.ErrOutOfStock):
+		http.Error(w, "Fuel depot is out of stock", http.StatusConflict)
+	case errors.Is(err, game.ErrExceedsStock):
+		http.Error(w, "Requested fuel exceeds depot stock", http.StatusConflict)
+	case errors.Is(err, game.ErrNegativeQuantity):
+		http.Error(w, "Amount and budget must not be negative", http.StatusBadRequest)
+	case errors.Is(err, game.ErrInvalidRefuel):
+		http.Error(w, "Specify either an amount or a budget, not both", http.StatusBadRequest)
+	default:
+		http.Error(w, "Invalid refuel request", http.StatusBadRequest)
+	}
+}
+
+// HandleBuyModule purchases and installs a ship upgrade.
+// Enforces the upgrade tree (prerequisites, tiers, stacking and exclusive groups).
+// Evaluates "buy_module" achievements after installation.
+func HandleBuyModule(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	var req BuyModuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	ship := p.ActiveShip()
+
+	if !game.HasService(ship.LocationKey, game.ServiceShipyard) {
+		http.Error(w, "Upgrade service unavailable at this location", http.StatusForbidden)
+		return
 	}
 
-	game.PlayerShip.ActiveContracts = remainingContracts
-	game.PlayerShip.Credits += payoutTotal
+	if err := game.BuyModule(p, req.ModuleKey); err != nil {
+		writeModuleError(w, err)
+		return
+	}
+	notifyAchievements(hub, p, game.AchievementOnBuyModule) // Defined in achievements.go
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(game.PlayerShip)
+	json.NewEncoder(w).Encode(statusOf(p))
 }
 
-// HandleRefuel fills the tank to max capacity for a credit fee.
-func HandleRefuel(w http.ResponseWriter, r *http.Request) {
+// HandleUninstallModule removes an installed module and places it in storage.
+// The module's stat effect is reversed and its slot is freed.
+func HandleUninstallModule(w http.ResponseWriter, r *http.Request) {
+	var req BuyModuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
 	game.DataLock.Lock()
 	defer game.DataLock.Unlock()
 
-	fuelNeeded := game.PlayerShip.MaxFuel - game.PlayerShip.Fuel
-	if fuelNeeded <= 0 {
-		http.Error(w, "Tank is already full", http.StatusBadRequest)
+	p, ok := currentPlayer(w, r)
+	if !ok {
 		return
 	}
 
-	// Cost calculation: Fuel Needed / 100 * PricePerUnit
-	// (Rounded down by integer division logic, consider adjusting if precision needed)
-	cost := (int(fuelNeeded) / 100) * game.CurrentUniverse.BalanceConfig.FuelCostPerUnit
+	ship := p.ActiveShip()
 
-	if game.PlayerShip.Credits < cost {
-		http.Error(w, "Insufficient credits", http.StatusForbidden)
+	if !game.HasService(ship.LocationKey, game.ServiceShipyard) {
+		http.Error(w, "Upgrade service unavailable at this location", http.StatusForbidden)
 		return
 	}
 
-	game.PlayerShip.Credits -= cost
-	game.PlayerShip.Fuel = game.PlayerShip.MaxFuel
+	if err := game.UninstallModule(ship, req.ModuleKey); err != nil {
+		writeModuleError(w, err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(game.PlayerShip)
+	json.NewEncoder(w).Encode(statusOf(p))
 }
 
-// HandleBuyModule purchases and installs a ship upgrade.
-func HandleBuyModule(w http.ResponseWriter, r *http.Request) {
+// HandleInstallModule re-installs a module from storage into a free slot.
+func HandleInstallModule(w http.ResponseWriter, r *http.Request) {
 	var req BuyModuleRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Bad Request", http.StatusBadRequest)
@@ -249,41 +423,145 @@ func HandleBuyModule(w http.ResponseWriter, r *http.Request) {
 	game.DataLock.Lock()
 	defer game.DataLock.Unlock()
 
-	if game.PlayerShip.LocationKey != "planet_prime" {
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	ship := p.ActiveShip()
+
+	if !game.HasService(ship.LocationKey, game.ServiceShipyard) {
 		http.Error(w, "Upgrade service unavailable at this location", http.StatusForbidden)
 		return
 	}
-	if len(game.PlayerShip.InstalledModules) >= game.PlayerShip.MaxModuleSlots {
-		http.Error(w, "No module slots available", http.StatusConflict)
+
+	if err := game.InstallModule(ship, req.ModuleKey); err != nil {
+		writeModuleError(w, err)
 		return
 	}
 
-	mod := game.GetModule(req.ModuleKey)
-	if mod == nil {
-		http.Error(w, "Module not found", http.StatusNotFound)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleSellModule sells an installed or stored module back to the shipyard.
+// Refunds 'module_resale_rate' of the module's Cost.
+func HandleSellModule(w http.ResponseWriter, r *http.Request) {
+	var req SellModuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
 		return
 	}
-	if game.PlayerShip.Credits < mod.Cost {
-		http.Error(w, "Insufficient Credits", http.StatusPaymentRequired)
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
 		return
 	}
 
-	// Apply Purchase
-	game.PlayerShip.Credits -= mod.Cost
-	game.PlayerShip.InstalledModules = append(game.PlayerShip.InstalledModules, *mod)
+	ship := p.ActiveShip()
+
+	if !game.HasService(ship.LocationKey, game.ServiceShipyard) {
+		http.Error(w, "Upgrade service unavailable at this location", http.StatusForbidden)
+		return
+	}
 
-	// Apply Stat Modifier
-	// Note: In a more complex system, this might be calculated dynamically
-	// rather than permanently mutating the base stats.
-	switch mod.StatModifier {
-	case "cargo_capacity":
-		game.PlayerShip.CargoCapacity += mod.StatValue
-	case "passenger_slots":
-		game.PlayerShip.PassengerSlots += mod.StatValue
+	refund, err := game.SellModule(p, req.ModuleKey, req.Stored)
+	if err != nil {
+		writeModuleError(w, err)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(game.PlayerShip)
+	json.NewEncoder(w).Encode(SellModuleResponse{Refund: refund, Player: statusOf(p)})
+}
+
+// writeOperationError maps game accept/travel errors to HTTP responses.
+func writeOperationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrContractNotFound):
+		http.Error(w, "Contract not found", http.StatusNotFound)
+	case errors.Is(err, game.ErrCargoFull):
+		http.Error(w, "Insufficient Cargo Space", http.StatusConflict)
+	case errors.Is(err, game.ErrPassengersFull):
+		http.Error(w, "Insufficient Passenger Slots", http.StatusConflict)
+	case errors.Is(err, game.ErrComfortTooLow):
+		http.Error(w, "Ship comfort rating too low for these passengers", http.StatusConflict)
+	case errors.Is(err, game.ErrContainmentRequired):
+		http.Error(w, "Hazardous cargo requires a containment module", http.StatusConflict)
+	case errors.Is(err, game.ErrContrabandOrigin):
+		http.Error(w, "Contraband cannot be loaded here", http.StatusForbidden)
+	case errors.Is(err, game.ErrInvalidQuantity):
+		http.Error(w, "Invalid quantity ("+err.Error()+")", http.StatusBadRequest)
+	case errors.Is(err, game.ErrNotSplittable):
+		http.Error(w, "Only single-stop cargo contracts can be split", http.StatusBadRequest)
+	case errors.Is(err, game.ErrDestinationInvalid):
+		http.Error(w, "Destination invalid", http.StatusNotFound)
+	case errors.Is(err, game.ErrInsufficientFuel):
+		http.Error(w, "Insufficient Fuel for current mass", http.StatusPaymentRequired)
+	case errors.Is(err, game.ErrInsufficientCredits):
+		http.Error(w, "Insufficient Credits", http.StatusPaymentRequired)
+	case errors.Is(err, game.ErrNotPostable):
+		http.Error(w, "Only single-stop cargo contracts of your own can be posted", http.StatusConflict)
+	case errors.Is(err, game.ErrAlreadyAtTarget):
+		http.Error(w, "Contract destination is this planet", http.StatusConflict)
+	case errors.Is(err, game.ErrOwnPosting):
+		http.Error(w, "Cannot haul your own posting (withdraw it instead)", http.StatusConflict)
+	case errors.Is(err, game.ErrInvalidAmount):
+		http.Error(w, "Payout must be positive", http.StatusBadRequest)
+	case errors.Is(err, game.ErrStandingTooLow):
+		http.Error(w, "Faction standing too low for this contract", http.StatusForbidden)
+	case errors.Is(err, game.ErrTransfersLocked):
+		http.Error(w, "Not enough lifetime earnings to send value to other players", http.StatusForbidden)
+	case errors.Is(err, game.ErrTransferCap):
+		http.Error(w, "Daily transfer limit reached", http.StatusTooManyRequests)
+	case errors.Is(err, game.ErrInDebt):
+		http.Error(w, "Repay your debt before sending value to other players", http.StatusConflict)
+	default:
+		http.Error(w, "Invalid Request", http.StatusBadRequest)
+	}
+}
+
+// writeModuleError maps game module errors to HTTP responses.
+func writeModuleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrModuleNotInstalled):
+		http.Error(w, "Module not installed", http.StatusNotFound)
+	case errors.Is(err, game.ErrModuleNotStored):
+		http.Error(w, "Module not in storage", http.StatusNotFound)
+	case errors.Is(err, game.ErrNoModuleSlots):
+		http.Error(w, "No module slots available", http.StatusConflict)
+	case errors.Is(err, game.ErrCapacityInUse):
+		http.Error(w, "Module capacity is in use by active contracts", http.StatusConflict)
+	case errors.Is(err, game.ErrTankInUse):
+		http.Error(w, "Module fuel capacity is in use", http.StatusConflict)
+	case errors.Is(err, game.ErrSlotsInUse):
+		http.Error(w, "Module slots are in use by other modules", http.StatusConflict)
+	case errors.Is(err, game.ErrComfortInUse):
+		http.Error(w, "Module comfort is required by passengers on board", http.StatusConflict)
+	case errors.Is(err, game.ErrContainmentInUse):
+		http.Error(w, "Module containment is required by hazardous cargo on board", http.StatusConflict)
+	case errors.Is(err, game.ErrModuleNotFound):
+		http.Error(w, "Module not found", http.StatusNotFound)
+	case errors.Is(err, game.ErrModuleNotSold):
+		http.Error(w, "Module not sold at this shipyard", http.StatusNotFound)
+	case errors.Is(err, game.ErrInsufficientCredits):
+		http.Error(w, "Insufficient Credits", http.StatusPaymentRequired)
+	case errors.Is(err, game.ErrModuleMaxStack):
+		http.Error(w, "Maximum copies of this module already installed", http.StatusConflict)
+	case errors.Is(err, game.ErrModuleExclusive):
+		http.Error(w, "Conflicts with an installed module", http.StatusConflict)
+	case errors.Is(err, game.ErrModuleObsolete):
+		http.Error(w, "Same or higher tier already installed", http.StatusConflict)
+	case errors.Is(err, game.ErrModuleRequired):
+		http.Error(w, "Another installed module requires this module", http.StatusConflict)
+	case errors.Is(err, game.ErrModulePrerequisite), errors.Is(err, game.ErrReputationTooLow), errors.Is(err, game.ErrEarningsTooLow), errors.Is(err, game.ErrStandingTooLow):
+		http.Error(w, "Module locked: "+err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, "Invalid module request", http.StatusBadRequest)
+	}
 }
 
 // HandleTravelQuote provides a "Pre-flight check".
@@ -298,8 +576,15 @@ func HandleTravelQuote(w http.ResponseWriter, r *http.Request) {
 	game.DataLock.RLock()
 	defer game.DataLock.RUnlock()
 
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	ship := p.ActiveShip()
+
 	dest := game.GetPlanet(req.DestinationKey)
-	current := game.GetPlanet(game.PlayerShip.LocationKey)
+	current := game.GetPlanet(ship.LocationKey)
 
 	if dest == nil {
 		http.Error(w, "Destination invalid", http.StatusNotFound)
@@ -307,14 +592,15 @@ func HandleTravelQuote(w http.ResponseWriter, r *http.Request) {
 	}
 
 	dist := game.CalculateDistance(current.Coordinates, dest.Coordinates)
-	currentBurn := game.CalculateCurrentBurn()
+	currentBurn := game.CalculateRouteBurn(ship, ship.Fuel, dest.Key)
 	fuelNeeded := dist * currentBurn
 
 	resp := TravelQuoteResponse{
-		Distance:  dist,
-		FuelCost:  fuelNeeded,
-		CanAfford: game.PlayerShip.Fuel >= fuelNeeded,
-		BurnRate:  currentBurn,
+		Distance:        dist,
+		FuelCost:        fuelNeeded,
+		CanAfford:       ship.Fuel >= fuelNeeded,
+		BurnRate:        currentBurn,
+		DestinationFuel: game.GetFuelMarket(dest.Key),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -322,7 +608,7 @@ func HandleTravelQuote(w http.ResponseWriter, r *http.Request) {
 }
 
 // HandleDropContract discards a contract.
-// NOTE: This currently does not penalize the player. Future versions should add a reputation hit.
+// Dropping a job costs reputation (see game.ReputationPerDrop).
 func HandleDropContract(w http.ResponseWriter, r *http.Request) {
 	var req ContractRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -333,25 +619,128 @@ func HandleDropContract(w http.ResponseWriter, r *http.Request) {
 	game.DataLock.Lock()
 	defer game.DataLock.Unlock()
 
-	foundIdx := -1
-	for i, c := range game.PlayerShip.ActiveContracts {
-		if c.ID == req.ContractID {
-			foundIdx = i
-			break
-		}
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
 	}
 
-	if foundIdx == -1 {
+	// Reputation loss and forfeited collateral are handled by the game package (escrow.go)
+	if _, err := game.DropContract(p, p.ActiveShip(), req.ContractID); err != nil {
 		http.Error(w, "Contract not found on ship", http.StatusNotFound)
 		return
 	}
 
-	// Remove from slice
-	game.PlayerShip.ActiveContracts = append(
-		game.PlayerShip.ActiveContracts[:foundIdx],
-		game.PlayerShip.ActiveContracts[foundIdx+1:]...,
-	)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandlePostContract hands a cargo contract from the hold to the local job board.
+// The offered payout is taken into escrow and paid to whoever delivers the goods.
+func HandlePostContract(w http.ResponseWriter, r *http.Request) {
+	var req PostContractRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := game.PostContract(p, req.ContractID, req.Payout); err != nil {
+		writeOperationError(w, err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(game.PlayerShip)
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleWithdrawPosting takes an unaccepted posting back into the hold and refunds its payout.
+func HandleWithdrawPosting(w http.ResponseWriter, r *http.Request) {
+	var req ContractRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := game.WithdrawPosting(p, req.ContractID); err != nil {
+		writeOperationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleMergeCargo combines cargo contracts in the hold that carry the same goods to the same planet.
+func HandleMergeCargo(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	game.MergeCargo(p.ActiveShip())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleGetMarketHistory returns the recorded heat/price time-series for one Planet/Commodity pair.
+// Query Params: planet, commodity (required), from, to (Unix seconds), bucket (seconds).
+// If 'bucket' is set, samples are downsampled into aggregates instead of returned raw.
+func HandleGetMarketHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	planetKey := q.Get("planet")
+	itemKey := q.Get("commodity")
+	if planetKey == "" || itemKey == "" {
+		http.Error(w, "planet and commodity are required", http.StatusBadRequest)
+		return
+	}
+
+	var from, to, bucket int64
+	for name, dst := range map[string]*int64{"from": &from, "to": &to, "bucket": &bucket} {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 0 {
+			http.Error(w, "Invalid "+name, http.StatusBadRequest)
+			return
+		}
+		*dst = v
+	}
+
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	if game.GetPlanet(planetKey) == nil || game.GetCommodity(itemKey) == nil {
+		http.Error(w, "Planet or Commodity not found", http.StatusNotFound)
+		return
+	}
+
+	samples := game.QueryMarketHistory(planetKey, itemKey, from, to)
+
+	w.Header().Set("Content-Type", "application/json")
+	if bucket > 0 {
+		json.NewEncoder(w).Encode(game.DownsampleMarketHistory(samples, bucket))
+		return
+	}
+	json.NewEncoder(w).Encode(samples)
 }
@@ -7,7 +7,8 @@ Description:
     It maintains a registry of all active clients (players connected via the frontend)
     and manages the broadcast channel. When the main loop or an event handler
     sends a message to 'Broadcast', this Hub ensures it is written to the
-    sockets of every connected user.
+    sockets of every connected user. Messages meant for some players only
+    (e.g., corporation chat) go through 'SendTo' instead.
 
     Architecture:
     - Hub: The singleton manager.
@@ -18,6 +19,7 @@ Description:
 package api
 
 import (
+	"encoding/json"
 	"log"
 	"net/http"
 
@@ -35,9 +37,16 @@ type Message struct {
 // Client represents a single connected player/browser tab.
 // It acts as a middleman between the websocket connection and the Hub.
 type Client struct {
-	hub  *Hub            // Reference to the central Hub
-	conn *websocket.Conn // The actual low-level WebSocket connection
-	send chan []byte     // Buffered channel for outbound messages
+	hub      *Hub            // Reference to the central Hub
+	conn     *websocket.Conn // The actual low-level WebSocket connection
+	send     chan []byte     // Buffered channel for outbound messages
+	playerID string          // Player the connection belongs to (resolved by PlayerMiddleware)
+}
+
+// directMessage is a message addressed to a set of players.
+type directMessage struct {
+	playerIDs map[string]bool
+	data      []byte
 }
 
 // Hub maintains the set of active clients and broadcasts messages to them.
@@ -55,6 +64,9 @@ type Hub struct {
 
 	// Unregister requests from clients.
 	unregister chan *Client
+
+	// Messages for specific players only.
+	direct chan directMessage
 }
 
 // NewHub creates a new Hub instance.
@@ -64,6 +76,7 @@ func NewHub() *Hub {
 		Broadcast:  make(chan []byte), // FIX: Capitalized to match Struct
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		direct:     make(chan directMessage),
 		clients:    make(map[*Client]bool),
 	}
 }
@@ -89,18 +102,55 @@ func (h *Hub) Run() {
 			// A message came in (likely from the Market Heartbeat).
 			// Send it to everyone.
 			for client := range h.clients {
-				select {
-				case client.send <- message:
-				default:
-					// If the client's send buffer is full, assume they hung or disconnected.
-					close(client.send)
-					delete(h.clients, client)
+				h.deliver(client, message)
+			}
+
+		case msg := <-h.direct:
+			// Only the addressed players receive it (on every tab they have open).
+			for client := range h.clients {
+				if msg.playerIDs[client.playerID] {
+					h.deliver(client, msg.data)
 				}
 			}
 		}
 	}
 }
 
+// deliver queues a message for one client.
+func (h *Hub) deliver(client *Client, message []byte) {
+	select {
+	case client.send <- message:
+	default:
+		// If the client's send buffer is full, assume they hung or disconnected.
+		close(client.send)
+		delete(h.clients, client)
+	}
+}
+
+// Publish wraps a payload in a system Message and broadcasts it to every client.
+func (h *Hub) Publish(msgType string, payload interface{}) {
+	jsonBytes, err := json.Marshal(Message{Type: msgType, Payload: payload, Sender: "system"})
+	if err != nil {
+		log.Printf("ERROR: Failed to marshal %s: %v", msgType, err)
+		return
+	}
+	h.Broadcast <- jsonBytes
+}
+
+// SendTo delivers a Message to the connected clients of the listed players only.
+func (h *Hub) SendTo(playerIDs []string, msg Message) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("ERROR: Failed to marshal %s: %v", msg.Type, err)
+		return
+	}
+	ids := make(map[string]bool)
+	for _, id := range playerIDs {
+		ids[id] = true
+	}
+	h.direct <- directMessage{playerIDs: ids, data: jsonBytes}
+}
+
 // upgrader configures the WebSocket handshake.
 // CheckOrigin returns true to allow connections from any host (CORS permissive for development).
 var upgrader = websocket.Upgrader{
@@ -119,7 +169,7 @@ func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create the client wrapper
-	client := &Client{hub: hub, conn: conn, send: make(chan []byte, 256)}
+	client := &Client{hub: hub, conn: conn, send: make(chan []byte, 256), playerID: playerID(r)}
 
 	// Register the client with the Hub loop
 	client.hub.register <- client
@@ -0,0 +1,67 @@
+/*
+Package api
+File: ledger.go
+Description:
+    Exposes the player's credit ledger.
+
+    Key Responsibilities:
+    - History Endpoint: Filtered, paginated transaction history (newest first).
+    - Reconcile Endpoint: Checks that the ledger adds up to the wallet balance.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/everforgeworks/galaxies-burn-rate/internal/game"
+)
+
+// HandleGetLedger returns one page of the player's credit history.
+// Query Params: type, ref (exact match), from, to (Unix seconds), offset, limit.
+func HandleGetLedger(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := game.LedgerFilter{Type: q.Get("type"), Ref: q.Get("ref")}
+
+	var from, to, offset, limit int64
+	for name, dst := range map[string]*int64{"from": &from, "to": &to, "offset": &offset, "limit": &limit} {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 0 {
+			http.Error(w, "Invalid "+name, http.StatusBadRequest)
+			return
+		}
+		*dst = v
+	}
+	filter.From, filter.To = from, to
+
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.QueryLedger(p, filter, int(offset), int(limit)))
+}
+
+// HandleReconcileLedger compares the sum of the ledger with the current wallet balance.
+func HandleReconcileLedger(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.ReconcileLedger(p))
+}
@@ -0,0 +1,349 @@
+/*
+Package api
+File: players.go
+Description:
+    Identifies which player a request belongs to and exposes fleet management.
+
+    Players register once via /api/register, which returns a secret session
+    token. Every other request carries it in the 'Authorization: Bearer' header.
+    Only the WebSocket handshake may pass it as the 'token' query parameter,
+    because browsers cannot set headers there; URLs end up in logs and history.
+    The public player ID only addresses other players (transfers, invites).
+
+    Key Responsibilities:
+    - PlayerMiddleware: Resolves the token and rejects unauthenticated requests.
+    - Registration Endpoint: Creates a player with the starting ship, credits and token.
+    - Fleet Endpoints: Listing, buying and switching ships.
+    - Route Endpoints: Orders, logs and profit reports of automated ships.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/everforgeworks/galaxies-burn-rate/internal/game"
+)
+
+// playerIDPattern restricts IDs to short, URL and log friendly strings.
+var playerIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// playerKey is the context key under which PlayerMiddleware stores the player ID.
+type playerKey struct{}
+
+// PlayerStatus is the standard response of player endpoints:
+// the player's wallet, career and fleet, plus the ship they are currently flying.
+type PlayerStatus struct {
+	*game.Player
+	Ship *game.Ship `json:"ship"`
+}
+
+type RegisterRequest struct {
+	PlayerID string `json:"player_id"`
+}
+
+// RegisterResponse is the new player plus the session token. It is only ever returned here.
+type RegisterResponse struct {
+	PlayerStatus
+	Token string `json:"token"`
+}
+
+type BuyHullRequest struct {
+	HullKey string `json:"hull_key"`
+	Name    string `json:"name"` // Optional ship name (defaults to the hull name)
+}
+
+// SwitchShipRequest selects the ship to fly. TransferContracts moves the current cargo across.
+type SwitchShipRequest struct {
+	ShipID            string `json:"ship_id"`
+	TransferContracts bool   `json:"transfer_contracts"`
+}
+
+// RouteRequest assigns orders to a secondary ship.
+type RouteRequest struct {
+	ShipID string           `json:"ship_id"`
+	Steps  []game.RouteStep `json:"steps"`
+	Repeat bool             `json:"repeat"` // Loop the route until cancelled
+}
+
+type ShipRequest struct {
+	ShipID string `json:"ship_id"`
+}
+
+// PlayerMiddleware resolves the session token of every request to a player. Requests without
+// a valid token may only call /api/register. Wrap the mux with it in main.go so handlers can
+// rely on currentPlayer.
+func PlayerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/register" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found && r.URL.Path == "/ws" {
+			token = r.URL.Query().Get("token")
+		}
+
+		game.DataLock.RLock()
+		p := game.PlayerByToken(token)
+		game.DataLock.RUnlock()
+		if p == nil {
+			http.Error(w, "Missing or invalid session token (register first)", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), playerKey{}, p.ID)))
+	})
+}
+
+// playerID returns the ID resolved by PlayerMiddleware, or "" for unauthenticated requests.
+func playerID(r *http.Request) string {
+	id, _ := r.Context().Value(playerKey{}).(string)
+	return id
+}
+
+// currentPlayer returns the player making the request.
+// Writes a 401 and returns false if the request was not authenticated by PlayerMiddleware.
+// Note: Caller must hold game.DataLock
+func currentPlayer(w http.ResponseWriter, r *http.Request) (*game.Player, bool) {
+	if p := game.GetPlayer(playerID(r)); p != nil {
+		return p, true
+	}
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	return nil, false
+}
+
+// statusOf builds the standard response for a player.
+func statusOf(p *game.Player) PlayerStatus {
+	return PlayerStatus{Player: p, Ship: p.ActiveShip()}
+}
+
+// HandleRegister creates a player with the starting ship and credits.
+// The response carries the session token that authenticates all further requests.
+func HandleRegister(w http.ResponseWriter, r *http.Request) {
+	var req RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	if !playerIDPattern.MatchString(req.PlayerID) {
+		http.Error(w, "Invalid Player ID", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, err := game.RegisterPlayer(req.PlayerID)
+	if errors.Is(err, game.ErrPlayerExists) {
+		http.Error(w, "Player already registered", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Registration failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RegisterResponse{PlayerStatus: statusOf(p), Token: p.Token})
+}
+
+// HandleGetHulls returns the hulls sold at the current location's shipyard.
+func HandleGetHulls(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.OfferHulls(p))
+}
+
+// HandleBuyHull purchases an additional ship. It joins the fleet docked at the current location.
+func HandleBuyHull(w http.ResponseWriter, r *http.Request) {
+	var req BuyHullRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if !game.HasService(p.ActiveShip().LocationKey, game.ServiceShipyard) {
+		http.Error(w, "Shipyard unavailable at this location", http.StatusForbidden)
+		return
+	}
+
+	if _, err := game.BuyHull(p, req.HullKey, req.Name); err != nil {
+		writeFleetError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleSwitchShip changes the active ship. Both ships must be docked at the same planet.
+func HandleSwitchShip(w http.ResponseWriter, r *http.Request) {
+	var req SwitchShipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.SwitchShip(p, req.ShipID, req.TransferContracts); err != nil {
+		writeFleetError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleAssignRoute gives a secondary ship automated orders.
+// The route runs on the server heartbeat, one step per tick.
+func HandleAssignRoute(w http.ResponseWriter, r *http.Request) {
+	var req RouteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := game.AssignRoute(p, req.ShipID, req.Steps, req.Repeat); err != nil {
+		writeFleetError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleCancelRoute stops a ship's automated orders.
+func HandleCancelRoute(w http.ResponseWriter, r *http.Request) {
+	var req ShipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := game.CancelRoute(p, req.ShipID); err != nil {
+		writeFleetError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleGetRouteLog returns the recent route events of one ship.
+// Query Params: ?ship_id=SHP-...
+func HandleGetRouteLog(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	ship := p.GetShip(r.URL.Query().Get("ship_id"))
+	if ship == nil {
+		writeFleetError(w, game.ErrShipNotFound)
+		return
+	}
+
+	log := ship.RouteLog
+	if log == nil {
+		log = []game.RouteLogEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(log)
+}
+
+// HandleGetFleetReport returns the profit report of every ship in the player's fleet.
+func HandleGetFleetReport(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.BuildFleetReport(p))
+}
+
+// writeFleetError maps game fleet errors to HTTP responses.
+func writeFleetError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrHullNotFound):
+		http.Error(w, "Hull not found", http.StatusNotFound)
+	case errors.Is(err, game.ErrHullNotSold):
+		http.Error(w, "Hull not sold at this shipyard", http.StatusNotFound)
+	case errors.Is(err, game.ErrInsufficientCredits):
+		http.Error(w, "Insufficient Credits", http.StatusPaymentRequired)
+	case errors.Is(err, game.ErrShipNotFound):
+		http.Error(w, "Ship not found in fleet", http.StatusNotFound)
+	case errors.Is(err, game.ErrShipNotDocked):
+		http.Error(w, "Ship is not docked at this location", http.StatusConflict)
+	case errors.Is(err, game.ErrShipAlreadyFlown):
+		http.Error(w, "Ship is already active", http.StatusBadRequest)
+	case errors.Is(err, game.ErrContractsAboard):
+		http.Error(w, "Deliver or transfer active contracts first", http.StatusConflict)
+	case errors.Is(err, game.ErrTransferCapacity):
+		http.Error(w, "Target ship cannot hold the contracts", http.StatusConflict)
+	case errors.Is(err, game.ErrContainmentRequired):
+		http.Error(w, "Target ship has no containment for hazardous cargo", http.StatusConflict)
+	case errors.Is(err, game.ErrContrabandOrigin):
+		http.Error(w, "Contraband cannot be transferred here", http.StatusForbidden)
+	case errors.Is(err, game.ErrShipIsActive):
+		http.Error(w, "The active ship cannot run a route", http.StatusConflict)
+	case errors.Is(err, game.ErrNoRoute):
+		http.Error(w, "Ship has no route", http.StatusNotFound)
+	case errors.Is(err, game.ErrInvalidRoute):
+		http.Error(w, "Invalid route ("+err.Error()+")", http.StatusBadRequest)
+	default:
+		http.Error(w, "Invalid fleet request", http.StatusBadRequest)
+	}
+}
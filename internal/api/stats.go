@@ -0,0 +1,73 @@
+/*
+Package api
+File: stats.go
+Description:
+    Exposes player statistics and leaderboards.
+
+    Key Responsibilities:
+    - Stats Endpoint: The player's statistics in every time window plus net worth.
+    - Leaderboard Endpoint: All players ranked by one metric in one window.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/everforgeworks/galaxies-burn-rate/internal/game"
+)
+
+// HandleGetStats returns the player's statistics ("all", "hour", "day", "week") and net worth.
+func HandleGetStats(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.BuildStatsReport(p))
+}
+
+// HandleGetLeaderboard ranks all players.
+// Query Params: metric (default "net_worth"), window (default "all"), limit.
+func HandleGetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	metric, window := q.Get("metric"), q.Get("window")
+	if metric == "" {
+		metric = game.MetricNetWorth
+	}
+	if window == "" {
+		window = "all"
+	}
+	limit := 0
+	if raw := q.Get("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = v
+	}
+
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	entries, err := game.Leaderboard(metric, window, limit)
+	switch {
+	case errors.Is(err, game.ErrUnknownMetric):
+		http.Error(w, "Unknown metric", http.StatusBadRequest)
+		return
+	case errors.Is(err, game.ErrUnknownWindow):
+		http.Error(w, "Unknown window (all, hour, day, week)", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
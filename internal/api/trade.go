@@ -0,0 +1,184 @@
+/*
+Package api
+File: trade.go
+Description:
+    Exposes exchanges between players. The sending side is always the player
+    authenticated by PlayerMiddleware; request bodies only name the recipient.
+
+    Key Responsibilities:
+    - Transfer Endpoint: Wiring credits to another player.
+    - Offer Endpoints: Proposing, listing, accepting and declining trades
+      (credits, subcontracted contracts and stored modules).
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/everforgeworks/galaxies-burn-rate/internal/game"
+)
+
+// TransferRequest wires credits to another player.
+type TransferRequest struct {
+	ToID   string `json:"to_id"`
+	Amount int    `json:"amount"`
+}
+
+// TradeRequest selects a pending trade offer.
+type TradeRequest struct {
+	OfferID string `json:"offer_id"`
+}
+
+// TradeOffersResponse lists the pending offers of a player.
+type TradeOffersResponse struct {
+	Incoming []game.TradeOffer `json:"incoming"`
+	Outgoing []game.TradeOffer `json:"outgoing"`
+}
+
+// HandleTransferCredits wires credits to another player.
+func HandleTransferCredits(w http.ResponseWriter, r *http.Request) {
+	var req TransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.TransferCredits(p, req.ToID, req.Amount); err != nil {
+		writeTradeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleGetTradeOffers returns the offers the player has received and sent.
+func HandleGetTradeOffers(w http.ResponseWriter, r *http.Request) {
+	game.DataLock.RLock()
+	defer game.DataLock.RUnlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	incoming, outgoing := game.PendingTrades(p)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TradeOffersResponse{Incoming: incoming, Outgoing: outgoing})
+}
+
+// HandleOfferTrade proposes a trade to a player docked at the same planet.
+// Body: to_id, credits, contracts, modules, share (percent kept of subcontracted payouts), request_credits.
+func HandleOfferTrade(w http.ResponseWriter, r *http.Request) {
+	var req game.TradeOffer
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	offer, err := game.OfferTrade(p, req)
+	if err != nil {
+		writeTradeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offer)
+}
+
+// HandleAcceptTrade executes a pending offer. Every item moves at once, or nothing does.
+func HandleAcceptTrade(w http.ResponseWriter, r *http.Request) {
+	var req TradeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := game.AcceptTrade(p, req.OfferID); err != nil {
+		writeTradeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusOf(p))
+}
+
+// HandleDeclineTrade declines a received offer or withdraws a sent one.
+func HandleDeclineTrade(w http.ResponseWriter, r *http.Request) {
+	var req TradeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	game.DataLock.Lock()
+	defer game.DataLock.Unlock()
+
+	p, ok := currentPlayer(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.DeclineTrade(p, req.OfferID); err != nil {
+		writeTradeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTradeError maps game trade errors to HTTP responses.
+// Capacity and cargo rule failures of the recipient's ship fall through to writeOperationError.
+func writeTradeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrPlayerNotFound):
+		http.Error(w, "Player not found", http.StatusNotFound)
+	case errors.Is(err, game.ErrOfferNotFound):
+		http.Error(w, "Trade offer not found", http.StatusNotFound)
+	case errors.Is(err, game.ErrSelfTrade):
+		http.Error(w, "Cannot trade with yourself", http.StatusBadRequest)
+	case errors.Is(err, game.ErrEmptyOffer):
+		http.Error(w, "Trade offer contains nothing", http.StatusBadRequest)
+	case errors.Is(err, game.ErrInvalidAmount):
+		http.Error(w, "Amounts must be positive", http.StatusBadRequest)
+	case errors.Is(err, game.ErrInvalidShare):
+		http.Error(w, "Share must be between 0 and 100", http.StatusBadRequest)
+	case errors.Is(err, game.ErrNotDockedTogether):
+		http.Error(w, "Both players must be docked at the same planet", http.StatusConflict)
+	case errors.Is(err, game.ErrNotTransferable):
+		http.Error(w, "Contract is already subcontracted", http.StatusConflict)
+	case errors.Is(err, game.ErrModuleNotStored):
+		http.Error(w, "Module not in storage", http.StatusNotFound)
+	case errors.Is(err, game.ErrInsufficientCredits):
+		http.Error(w, "Insufficient Credits", http.StatusPaymentRequired)
+	default:
+		writeOperationError(w, err)
+	}
+}
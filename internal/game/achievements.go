@@ -0,0 +1,174 @@
+/*
+Package game
+File: achievements.go
+Description:
+    Achievements and milestone rewards.
+
+    Achievements are defined in the 'achievements' section of 'universe.yaml'
+    as a set of conditions, all of which must hold at once (e.g., "docked at
+    Drifter's End with isotopes aboard", "1000 units of ore delivered").
+    The API layer evaluates them after travelling, accepting a contract and
+    buying a module, and pushes every new unlock to the player over the
+    WebSocket hub.
+
+    Each achievement unlocks once per player and may grant credits and/or
+    a cosmetic (a title, paint job or decal the client can display).
+*/
+
+package game
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Achievement events: the moments at which achievements are evaluated.
+const (
+	AchievementOnTravel    = "travel"
+	AchievementOnAccept    = "accept"
+	AchievementOnBuyModule = "buy_module"
+)
+
+// EvaluateAchievements unlocks every achievement of the event whose conditions the player
+// now meets, and pays the rewards. Returns the new unlocks.
+// Note: Caller must hold DataLock
+func EvaluateAchievements(p *Player, event string) []AchievementUnlock {
+	unlocked := []AchievementUnlock{}
+	for _, a := range CurrentUniverse.Achievements {
+		if _, done := p.Achievements[a.Key]; done || (a.Event != "" && a.Event != event) || !a.met(p) {
+			continue
+		}
+		unlocked = append(unlocked, grantAchievement(p, a))
+	}
+	return unlocked
+}
+
+// met reports whether the player currently satisfies every condition of the achievement.
+func (a AchievementDefinition) met(p *Player) bool {
+	ship := p.ActiveShip()
+	if a.AtPlanet != "" && ship.LocationKey != a.AtPlanet {
+		return false
+	}
+	if a.Carrying != "" && !slices.ContainsFunc(ship.ActiveContracts, func(c Contract) bool { return c.ItemKey == a.Carrying }) {
+		return false
+	}
+	if a.FullHold {
+		cargo, _ := CalculateLoad(ship)
+		if cargo < ship.Effective.CargoCapacity {
+			return false
+		}
+	}
+	if a.ModuleInstalled != "" && findModule(ship.InstalledModules, a.ModuleInstalled) == -1 {
+		return false
+	}
+	if a.DeliveredEverywhere {
+		for _, planet := range CurrentUniverse.Planets {
+			if p.Stats.DeliveriesTo[planet.Key] == 0 {
+				return false
+			}
+		}
+	}
+	for item, units := range a.UnitsDelivered {
+		if p.Stats.UnitsDelivered[item] < units {
+			return false
+		}
+	}
+	for metric, minimum := range a.Stats {
+		if metricValue(p, p.Stats, metric) < minimum { // Defined in stats.go
+			return false
+		}
+	}
+	return true
+}
+
+// grantAchievement records an unlock and pays its rewards.
+func grantAchievement(p *Player, a AchievementDefinition) AchievementUnlock {
+	now := time.Now().Unix()
+	if p.Achievements == nil {
+		p.Achievements = make(map[string]int64)
+	}
+	p.Achievements[a.Key] = now
+
+	if a.RewardCredits > 0 {
+		p.Credits += a.RewardCredits
+		recordLedger(p, LedgerAchievement, a.RewardCredits, a.Key, fmt.Sprintf("Achievement unlocked: %s", a.Name))
+	}
+	if a.RewardCosmetic != "" && !slices.Contains(p.Cosmetics, a.RewardCosmetic) {
+		p.Cosmetics = append(p.Cosmetics, a.RewardCosmetic)
+	}
+	return AchievementUnlock{AchievementDefinition: a, UnlockedAt: now}
+}
+
+// ListAchievements returns every achievement and whether the player has unlocked it.
+// Note: Caller must hold DataLock
+func ListAchievements(p *Player) []AchievementStatus {
+	list := []AchievementStatus{}
+	for _, a := range CurrentUniverse.Achievements {
+		status := AchievementStatus{AchievementDefinition: a}
+		if at, ok := p.Achievements[a.Key]; ok {
+			status.Unlocked, status.UnlockedAt = true, at
+		}
+		list = append(list, status)
+	}
+	return list
+}
+
+// ValidateAchievements checks the 'achievements' section against the rest of the universe.
+func ValidateAchievements(u *Universe) error {
+	planets := make(map[string]bool)
+	for _, p := range u.Planets {
+		planets[p.Key] = true
+	}
+	items := map[string]bool{"passenger": true}
+	for _, c := range u.Commodities {
+		items[c.Key] = true
+	}
+	modules := make(map[string]bool)
+	for _, m := range u.ShipModules {
+		modules[m.Key] = true
+	}
+	events := map[string]bool{"": true, AchievementOnTravel: true, AchievementOnAccept: true, AchievementOnBuyModule: true}
+
+	seen := make(map[string]bool)
+	for _, a := range u.Achievements {
+		if a.Key == "" {
+			return fmt.Errorf("achievement %q: missing key", a.Name)
+		}
+		if seen[a.Key] {
+			return fmt.Errorf("achievement %q: duplicate key", a.Key)
+		}
+		seen[a.Key] = true
+
+		if !events[a.Event] {
+			return fmt.Errorf("achievement %q: unknown event %q", a.Key, a.Event)
+		}
+		if a.AtPlanet != "" && !planets[a.AtPlanet] {
+			return fmt.Errorf("achievement %q: unknown planet %q", a.Key, a.AtPlanet)
+		}
+		if a.Carrying != "" && !items[a.Carrying] {
+			return fmt.Errorf("achievement %q: unknown item %q", a.Key, a.Carrying)
+		}
+		if a.ModuleInstalled != "" && !modules[a.ModuleInstalled] {
+			return fmt.Errorf("achievement %q: unknown module %q", a.Key, a.ModuleInstalled)
+		}
+		for item := range a.UnitsDelivered {
+			if !items[item] {
+				return fmt.Errorf("achievement %q: unknown item %q", a.Key, item)
+			}
+		}
+		for metric := range a.Stats {
+			if !slices.Contains(leaderboardMetrics, metric) {
+				return fmt.Errorf("achievement %q: unknown stat %q", a.Key, metric)
+			}
+		}
+		if a.RewardCredits < 0 {
+			return fmt.Errorf("achievement %q: reward_credits must not be negative", a.Key)
+		}
+		if a.AtPlanet == "" && a.Carrying == "" && !a.FullHold && a.ModuleInstalled == "" &&
+			!a.DeliveredEverywhere && len(a.UnitsDelivered) == 0 && len(a.Stats) == 0 {
+			return fmt.Errorf("achievement %q: no conditions", a.Key)
+		}
+	}
+	return nil
+}
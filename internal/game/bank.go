@@ -0,0 +1,295 @@
+/*
+Package game
+File: bank.go
+Description:
+    Loans, debt and the way out of a dead end.
+
+    A player who burned their last credits away from a depot would otherwise
+    be stuck forever. This file provides the safety net:
+    1. Loans: Planets with a "bank" grant loans up to a reputation-based limit.
+       Interest is added to the debt on every economy tick (AccrueInterest).
+    2. Emergency Tow: A stranded ship is towed to the nearest depot. Whatever
+       the wallet can't cover is added to the debt.
+    3. Bankruptcy: A stranded player whose debt exceeds everything they own
+       (negative net worth) may start over with a fresh ship and StartingCredits.
+       Cargo, modules, postings, trade offers and reputation are lost.
+       While in debt, a player cannot send value to others (see trade.go),
+       so borrowed credits cannot be moved away before going bankrupt.
+
+    Tuned in the 'banking' section of 'universe.yaml'.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Banking errors.
+var (
+	ErrNoBank        = errors.New("no bank at this location")
+	ErrInvalidAmount = errors.New("amount must be positive")
+	ErrLoanLimit     = errors.New("loan would exceed the debt limit")
+	ErrNoDebt        = errors.New("player has no debt")
+	ErrNotStranded   = errors.New("ship is not stranded")
+	ErrSolvent       = errors.New("player's net worth still covers their debt")
+	ErrInDebt        = errors.New("player must repay their debt first")
+)
+
+// LoanLimit returns the maximum debt the banks allow the player.
+func LoanLimit(p *Player) int {
+	cfg := CurrentUniverse.Banking
+	return cfg.MaxLoan + cfg.LoanPerReputation*max(p.Reputation, 0)
+}
+
+// TakeLoan borrows credits at the bank where the active ship is docked.
+// Note: Caller must hold DataLock
+func TakeLoan(p *Player, amount int) error {
+	ship := p.ActiveShip()
+	if !HasService(ship.LocationKey, ServiceBank) {
+		return ErrNoBank
+	}
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if p.Debt+amount > LoanLimit(p) {
+		return ErrLoanLimit
+	}
+
+	p.Debt += amount
+	p.Credits += amount
+	recordLedger(p, LedgerLoan, amount, ship.LocationKey, fmt.Sprintf("Loan of %d (debt now %d)", amount, p.Debt))
+	return nil
+}
+
+// RepayLoan pays back debt at the bank where the active ship is docked.
+// An amount of 0 repays as much as the wallet allows. Returns the amount repaid.
+// Note: Caller must hold DataLock
+func RepayLoan(p *Player, amount int) (int, error) {
+	ship := p.ActiveShip()
+	if !HasService(ship.LocationKey, ServiceBank) {
+		return 0, ErrNoBank
+	}
+	if p.Debt == 0 {
+		return 0, ErrNoDebt
+	}
+	if amount < 0 {
+		return 0, ErrInvalidAmount
+	}
+	if amount == 0 {
+		amount = max(p.Credits, 0)
+	}
+	amount = min(amount, p.Debt)
+	if amount == 0 || amount > p.Credits {
+		return 0, ErrInsufficientCredits
+	}
+
+	p.Debt -= amount
+	p.Credits -= amount
+	recordLedger(p, LedgerRepayment, -amount, ship.LocationKey, fmt.Sprintf("Repaid %d (debt now %d)", amount, p.Debt))
+	return amount, nil
+}
+
+// AccrueInterest adds one economy tick of interest to every player's debt.
+// Called by the heartbeat in main.go. Returns the number of players in debt.
+func AccrueInterest() int {
+	DataLock.Lock()
+	defer DataLock.Unlock()
+
+	rate := CurrentUniverse.Banking.InterestRate
+	debtors := 0
+	for _, p := range Players {
+		if p.Debt <= 0 {
+			continue
+		}
+		// Round up: even the smallest debt costs something
+		p.Debt += int(math.Ceil(float64(p.Debt) * rate))
+		debtors++
+	}
+	return debtors
+}
+
+// canReachAny reports whether the ship could fly to any other planet if it carried 'fuel' units.
+func canReachAny(ship *Ship, fuel int64) bool {
+	origin := GetPlanet(ship.LocationKey)
+	if origin == nil {
+		return false
+	}
+	for _, p := range CurrentUniverse.Planets {
+		if p.Key != origin.Key && CalculateDistance(origin.Coordinates, p.Coordinates)*CalculateRouteBurn(ship, fuel, p.Key) <= fuel {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStranded reports whether a ship can't leave its planet on its own: it can't reach
+// any other planet, and the fuel it could buy here (with a loan, if there is a bank) wouldn't help.
+// Note: Caller must hold DataLock
+func IsStranded(p *Player, ship *Ship) bool {
+	if canReachAny(ship, ship.Fuel) {
+		return false
+	}
+
+	budget := max(p.Credits, 0)
+	if HasService(ship.LocationKey, ServiceBank) {
+		budget += max(LoanLimit(p)-p.Debt, 0)
+	}
+	if budget == 0 {
+		return true
+	}
+	quote, err := PlanRefuel(p, ship, 0, budget)
+	return err != nil || !canReachAny(ship, quote.FuelAfter)
+}
+
+// PlanTow quotes an emergency tow for a stranded ship to the nearest planet with a fuel depot,
+// preferring one that also has a bank so the player can borrow for fuel.
+// Note: Caller must hold DataLock
+func PlanTow(p *Player, ship *Ship) (TowResult, error) {
+	if !IsStranded(p, ship) {
+		return TowResult{}, ErrNotStranded
+	}
+	origin := GetPlanet(ship.LocationKey)
+	if origin == nil {
+		return TowResult{}, ErrDestinationInvalid
+	}
+
+	var best *Planet
+	bestDist, bestBank := int64(-1), false
+	for i := range CurrentUniverse.Planets {
+		target := &CurrentUniverse.Planets[i]
+		if target.Key == origin.Key || !HasService(target.Key, ServiceFuelDepot) {
+			continue
+		}
+		dist := CalculateDistance(origin.Coordinates, target.Coordinates)
+		bank := HasService(target.Key, ServiceBank)
+		if best == nil || (bank && !bestBank) || (bank == bestBank && dist < bestDist) {
+			best, bestDist, bestBank = target, dist, bank
+		}
+	}
+	if best == nil {
+		return TowResult{}, ErrNoFuelDepot
+	}
+
+	cfg := CurrentUniverse.Banking
+	cost := cfg.TowBaseFee + cfg.TowPerLY*int(bestDist)
+	paid := min(cost, max(p.Credits, 0))
+	return TowResult{
+		FromKey:  origin.Key,
+		ToKey:    best.Key,
+		Distance: bestDist,
+		Cost:     cost,
+		Paid:     paid,
+		Financed: cost - paid,
+	}, nil
+}
+
+// TowShip executes an emergency tow. The cargo stays aboard and no fuel is burned;
+// the fee is paid from the wallet and the rest is added to the debt.
+// Note: Caller must hold DataLock
+func TowShip(p *Player, ship *Ship) (TowResult, error) {
+	tow, err := PlanTow(p, ship)
+	if err != nil {
+		return TowResult{}, err
+	}
+
+	ship.LocationKey = tow.ToKey
+	p.Credits -= tow.Paid
+	p.Debt += tow.Financed
+	recordLedger(p, LedgerTow, -tow.Paid, tow.ToKey, fmt.Sprintf("Emergency tow of %s over %d LY (%d financed)", ship.Name, tow.Distance, tow.Financed))
+	return tow, nil
+}
+
+// checkBankruptcy reports whether the player may declare bankruptcy: the active ship
+// must be stranded and the player's net worth negative.
+func checkBankruptcy(p *Player) error {
+	if !IsStranded(p, p.ActiveShip()) {
+		return ErrNotStranded
+	}
+	if NetWorthOf(p).Total >= 0 { // Defined in stats.go
+		return ErrSolvent
+	}
+	return nil
+}
+
+// DeclareBankruptcy resets an insolvent player: all contracts are abandoned (collateral is
+// forfeited), postings and trade offers are cancelled, the fleet is replaced by a single
+// starting ship, reputation drops to zero and the wallet restarts at StartingCredits with no debt.
+// Note: Caller must hold DataLock
+func DeclareBankruptcy(p *Player) error {
+	if err := checkBankruptcy(p); err != nil {
+		return err
+	}
+
+	for _, ship := range p.Fleet {
+		for _, c := range ship.ActiveContracts {
+			forfeitCollateral(p, c, "bankruptcy")
+			if c.PostedBy != "" {
+				failPosting(c, "hauler went bankrupt")
+			}
+		}
+	}
+	for key, board := range AvailableContracts {
+		remaining := []Contract{}
+		for _, c := range board {
+			if c.PostedBy == p.ID {
+				failPosting(c, "poster went bankrupt")
+				continue
+			}
+			remaining = append(remaining, c)
+		}
+		AvailableContracts[key] = remaining
+	}
+	for id, offer := range TradeOffers {
+		if offer.FromID == p.ID || offer.ToID == p.ID {
+			delete(TradeOffers, id)
+		}
+	}
+
+	p.Fleet = []*Ship{}
+	p.ActiveShipID = ""
+	if hull := GetHull(StartingHullKey()); hull != nil {
+		ship := newShip(hull, StartingPlanetKey())
+		p.Fleet = append(p.Fleet, ship)
+		p.ActiveShipID = ship.ID
+	}
+
+	start := CurrentUniverse.BalanceConfig.StartingCredits
+	change := start - p.Credits
+	p.Credits = start
+	p.Debt = 0
+	p.Reputation = 0
+	p.Bankruptcies++
+	recordLedger(p, LedgerBankruptcy, change, "", "Bankruptcy: debt written off, fresh start")
+	return nil
+}
+
+// BuildBankStatus describes the player's debt and options at the active ship's location.
+// Note: Caller must hold DataLock
+func BuildBankStatus(p *Player) BankStatus {
+	ship := p.ActiveShip()
+	status := BankStatus{
+		BankHere:      HasService(ship.LocationKey, ServiceBank),
+		Debt:          p.Debt,
+		LoanLimit:     LoanLimit(p),
+		InterestRate:  CurrentUniverse.Banking.InterestRate,
+		Stranded:      IsStranded(p, ship),
+		CanGoBankrupt: checkBankruptcy(p) == nil,
+		Bankruptcies:  p.Bankruptcies,
+	}
+	if tow, err := PlanTow(p, ship); err == nil {
+		status.Tow = &tow
+	}
+	return status
+}
+
+// ValidateBanking checks the 'banking' section.
+func ValidateBanking(u *Universe) error {
+	cfg := u.Banking
+	if cfg.MaxLoan < 0 || cfg.LoanPerReputation < 0 || cfg.InterestRate < 0 || cfg.TowBaseFee < 0 || cfg.TowPerLY < 0 {
+		return errors.New("banking: values must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+/*
+Package game
+File: contracts.go
+Description:
+    Shapes of cargo contracts beyond a single A->B leg.
+    This includes:
+    1. Splitting: A cargo contract can be taken in part. The accepted share
+       keeps the contract ID and a pro-rated payout, the remainder stays on
+       the job board under a new ID.
+    2. Merging: Contracts in the hold that carry the same goods to the same
+       planet can be combined, so a full hold stays readable.
+    3. Multi-stop contracts: The load is dropped in portions at several
+       planets in order; every stop pays on arrival.
+    4. Chains: Completing a chained contract unlocks a follow-up offer at
+       the destination, reserved for the player who delivered it.
+
+    Passenger contracts are parties and never split, merge or chain.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+)
+
+// Contract shape defaults, used when 'game_balance' leaves the limits empty.
+const (
+	DefaultMaxStops       = 3
+	DefaultMaxChainLength = 3
+)
+
+// Contract splitting errors.
+var (
+	ErrInvalidQuantity = errors.New("invalid quantity")
+	ErrNotSplittable   = errors.New("only single-stop cargo contracts can be split")
+)
+
+// SplitContract divides a cargo contract into a share of 'qty' units and the remainder.
+// The share keeps the ID; the remainder gets a new one. Payout is pro-rated by quantity,
+// with any rounding going to the remainder so no credits are lost.
+func SplitContract(c Contract, qty int) (share, rest Contract, err error) {
+	if c.Type != "cargo" || len(c.Stops) > 0 || c.Chain != nil || c.PostedBy != "" {
+		return Contract{}, Contract{}, ErrNotSplittable
+	}
+	if qty <= 0 || qty >= c.Quantity {
+		return Contract{}, Contract{}, fmt.Errorf("%w: must be between 1 and %d", ErrInvalidQuantity, c.Quantity-1)
+	}
+
+	share, rest = c, c
+	share.Quantity = qty
+	share.Payout = c.Payout * qty / c.Quantity
+	share.Collateral = c.Collateral * qty / c.Quantity
+
+	rest.ID = newContractID("CRG")
+	rest.Quantity = c.Quantity - qty
+	rest.Payout = c.Payout - share.Payout
+	rest.Collateral = c.Collateral - share.Collateral
+	return share, rest, nil
+}
+
+// AcceptPartialContract takes 'qty' units of a cargo contract from the local job board.
+// A quantity of 0 (or the full quantity) accepts the whole contract like AcceptContract.
+// Triggers Market Scarcity (Source Heat) for the accepted units only.
+// Note: Caller must hold DataLock
+func AcceptPartialContract(p *Player, ship *Ship, contractID string, qty int) (Contract, error) {
+	location := ship.LocationKey
+	board := AvailableContracts[location]
+
+	foundIdx := findContract(board, contractID)
+	if foundIdx == -1 || !board[foundIdx].AvailableTo(p) {
+		return Contract{}, ErrContractNotFound
+	}
+	target := board[foundIdx]
+	if target.PostedBy == p.ID {
+		return Contract{}, ErrOwnPosting // Defined in postings.go
+	}
+	if qty == 0 || qty == target.Quantity {
+		return AcceptContract(p, ship, contractID)
+	}
+	if err := checkStanding(p, target); err != nil { // Defined in factions.go
+		return Contract{}, err
+	}
+
+	share, rest, err := SplitContract(target, qty)
+	if err != nil {
+		return Contract{}, err
+	}
+	share = PriceForPlayer(p, share)
+	if err := canLoad(ship, share); err != nil { // Defined in travel.go
+		return Contract{}, err
+	}
+	if err := holdCollateral(p, &share); err != nil { // Defined in escrow.go
+		return Contract{}, err
+	}
+
+	// The remainder takes the original's place on the board
+	ship.ActiveContracts = append(ship.ActiveContracts, share)
+	board[foundIdx] = rest
+
+	Market.RecordAcceptance(share.OriginKey, share.ItemKey, share.Quantity)
+	recordStats(p, func(s *PlayerStats) { s.Accepted++ }) // Defined in stats.go
+	return share, nil
+}
+
+// canMerge reports whether two contracts in the hold can be combined.
+// Perishable goods only merge if they have aged the same distance. Subcontracted goods
+// only merge under the same contractor and share, and standing must go to the same faction.
+func canMerge(a, b Contract) bool {
+	return a.Type == "cargo" && b.Type == "cargo" &&
+		len(a.Stops) == 0 && len(b.Stops) == 0 &&
+		a.Chain == nil && b.Chain == nil &&
+		a.PostedBy == "" && b.PostedBy == "" &&
+		a.ContractorID == b.ContractorID && a.ContractorShare == b.ContractorShare &&
+		a.Faction == b.Faction &&
+		a.ItemKey == b.ItemKey &&
+		a.DestinationKey == b.DestinationKey &&
+		slices.Equal(a.Traits, b.Traits) &&
+		(a.Travelled == b.Travelled || !HasTrait(a.Traits, TraitPerishable))
+}
+
+// MergeCargo combines cargo contracts in the hold that carry the same goods to the same planet.
+// The merged contract keeps the ID of the first one. Returns the number of contracts absorbed.
+// Note: Caller must hold DataLock
+func MergeCargo(ship *Ship) int {
+	merged := []Contract{}
+	absorbed := 0
+	for _, c := range ship.ActiveContracts {
+		idx := -1
+		for i := range merged {
+			if canMerge(merged[i], c) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			merged = append(merged, c)
+			continue
+		}
+		merged[idx].Quantity += c.Quantity
+		merged[idx].Payout += c.Payout
+		merged[idx].Collateral += c.Collateral
+		if c.Deadline > 0 && (merged[idx].Deadline == 0 || c.Deadline < merged[idx].Deadline) {
+			merged[idx].Deadline = c.Deadline // The earlier deadline binds the whole load
+		}
+		absorbed++
+	}
+
+	ship.ActiveContracts = merged
+	return absorbed
+}
+
+// AvailableTo reports whether a player may see and accept a contract on a job board.
+// Follow-up offers are reserved for the player who completed the previous step.
+func (c Contract) AvailableTo(p *Player) bool {
+	return c.OfferedTo == "" || c.OfferedTo == p.ID
+}
+
+// VisibleContracts returns the job board at a planet as seen by the player,
+// with payouts adjusted to the player's faction standing.
+// Note: Caller must hold DataLock
+func VisibleContracts(p *Player, planetKey string) []Contract {
+	visible := []Contract{}
+	for _, c := range AvailableContracts[planetKey] {
+		if c.AvailableTo(p) {
+			visible = append(visible, PriceForPlayer(p, c))
+		}
+	}
+	return visible
+}
+
+// shapeContract turns a freshly generated single-leg cargo contract into a
+// multi-stop or chained one, according to the chances in 'game_balance'.
+// Called by generateCargoJobs (economy.go).
+func shapeContract(job *Contract, origin *Planet, comm Commodity) {
+	cfg := CurrentUniverse.BalanceConfig
+	switch roll := rand.Float64(); {
+	case roll < cfg.MultiStopChance:
+		maxStops := cfg.MaxStops
+		if maxStops <= 0 {
+			maxStops = DefaultMaxStops
+		}
+		if maxStops >= 2 {
+			planItinerary(job, origin, comm, 2+rand.Intn(maxStops-1))
+		}
+	case roll < cfg.MultiStopChance+cfg.ChainChance:
+		maxLength := cfg.MaxChainLength
+		if maxLength <= 0 {
+			maxLength = DefaultMaxChainLength
+		}
+		if maxLength >= 2 {
+			job.Chain = &ContractChain{ID: newContractID("CHN"), Step: 1, Length: 2 + rand.Intn(maxLength-1)}
+		}
+	}
+}
+
+// planItinerary spreads the contract's load over 'n' stops. The stops are visited
+// nearest-first from the origin, and each one is priced like a single leg from the previous stop.
+func planItinerary(job *Contract, origin *Planet, comm Commodity, n int) {
+	candidates := []*Planet{}
+	for i := range CurrentUniverse.Planets {
+		if p := &CurrentUniverse.Planets[i]; p.Key != origin.Key {
+			candidates = append(candidates, p)
+		}
+	}
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	n = min(n, len(candidates), job.Quantity)
+	if n < 2 {
+		return
+	}
+	candidates = candidates[:n]
+
+	stops := []ContractStop{}
+	payout := 0
+	prev := origin
+	for len(candidates) > 0 {
+		// Fly to the closest remaining stop next
+		sort.Slice(candidates, func(i, j int) bool {
+			return CalculateDistance(prev.Coordinates, candidates[i].Coordinates) <
+				CalculateDistance(prev.Coordinates, candidates[j].Coordinates)
+		})
+		next := candidates[0]
+		candidates = candidates[1:]
+
+		// Even split; the last stop takes the rounding remainder
+		qty := job.Quantity / n
+		if len(candidates) == 0 {
+			qty = job.Quantity - qty*(n-1)
+		}
+
+		stop := ContractStop{
+			PlanetKey: next.Key,
+			Quantity:  qty,
+			Payout:    cargoPayout(prev, next, comm, qty),
+			Distance:  CalculateDistance(prev.Coordinates, next.Coordinates),
+		}
+		stops = append(stops, stop)
+		payout += stop.Payout
+		prev = next
+	}
+
+	job.Stops = stops
+	job.DestinationKey = stops[0].PlanetKey
+	job.Payout = payout
+}
+
+// deliverStop splits a multi-stop contract arriving at its next stop into the
+// portion dropped there and the contract that flies on to the following stop.
+func deliverStop(c Contract) (drop, rest Contract) {
+	stop := c.Stops[0]
+
+	drop = c
+	drop.Quantity = stop.Quantity
+	drop.Payout = stop.Payout
+	drop.Payout = DeliveryValue(drop) // Perishable goods decay per stop as well
+	drop.Collateral = c.Collateral * stop.Quantity / c.Quantity
+	drop.Stops = nil
+
+	rest = c
+	rest.Quantity -= stop.Quantity
+	rest.Payout -= stop.Payout
+	rest.Collateral -= drop.Collateral
+	rest.Stops = append([]ContractStop(nil), c.Stops[1:]...)
+	rest.DestinationKey = rest.Stops[0].PlanetKey
+	return drop, rest
+}
+
+// offerFollowUp posts the next contract of a chain at the destination of a completed one,
+// reserved for the player who delivered it. Returns nil if the chain is finished or
+// the planet has nothing to ship.
+func offerFollowUp(p *Player, done Contract) *Contract {
+	chain := done.Chain
+	if chain == nil || chain.Step >= chain.Length {
+		return nil
+	}
+	origin := GetPlanet(done.DestinationKey)
+	if origin == nil || len(CurrentUniverse.Planets) < 2 {
+		return nil
+	}
+
+	// Prefer what the planet produces; fall back to anything that may be loaded here
+	options := []Commodity{}
+	for _, key := range origin.Production {
+		if comm := GetCommodity(key); comm != nil && CanPickup(*comm, origin.Key) && !ProductionHalted(origin.Key, key) {
+			options = append(options, *comm)
+		}
+	}
+	if len(options) == 0 {
+		for _, comm := range CurrentUniverse.Commodities {
+			if CanPickup(comm, origin.Key) && !ProductionHalted(origin.Key, comm.Key) {
+				options = append(options, comm)
+			}
+		}
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	comm := options[rand.Intn(len(options))]
+
+	dest := &CurrentUniverse.Planets[rand.Intn(len(CurrentUniverse.Planets))]
+	for dest.Key == origin.Key {
+		dest = &CurrentUniverse.Planets[rand.Intn(len(CurrentUniverse.Planets))]
+	}
+
+	// Loyalty pays: every completed step raises the payout of the next one
+	qty := rand.Intn(21) + 5
+	payout := cargoPayout(origin, dest, comm, qty)
+	payout = int(float64(payout) * (1.0 + CurrentUniverse.BalanceConfig.ChainBonus*float64(chain.Step)))
+
+	offer := Contract{
+		ID:             newContractID("CRG"),
+		Type:           "cargo",
+		ItemName:       comm.Name,
+		ItemKey:        comm.Key,
+		Quantity:       qty,
+		MassPerUnit:    comm.Mass,
+		OriginKey:      origin.Key,
+		DestinationKey: dest.Key,
+		Payout:         payout,
+		Traits:         comm.Traits,
+		Collateral:     CollateralFor(comm, qty),
+		Chain:          &ContractChain{ID: chain.ID, Step: chain.Step + 1, Length: chain.Length},
+		OfferedTo:      p.ID,
+		Faction:        origin.Faction,
+	}
+	AvailableContracts[origin.Key] = append(AvailableContracts[origin.Key], offer)
+	return &offer
+}
+
+// ValidateContractShapes checks the multi-stop and chain settings in 'game_balance'.
+func ValidateContractShapes(u *Universe) error {
+	cfg := u.BalanceConfig
+	if cfg.MultiStopChance < 0 || cfg.ChainChance < 0 || cfg.MultiStopChance+cfg.ChainChance > 1 {
+		return errors.New("game_balance: multi_stop_chance and chain_chance must be between 0 and 1 combined")
+	}
+	if cfg.MaxStops < 0 || cfg.MaxChainLength < 0 || cfg.ChainBonus < 0 {
+		return errors.New("game_balance: contract shape values must not be negative")
+	}
+	return nil
+}
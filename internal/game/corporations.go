@@ -0,0 +1,448 @@
+/*
+Package game
+File: corporations.go
+Description:
+    Player corporations (guilds) with a shared treasury.
+    This includes:
+    1. Membership: Founding, joining and leaving a corporation. A player
+       belongs to at most one and can only join when invited by an officer.
+       The last member to leave dissolves it.
+    2. Roles: The founder leads, officers manage members and the treasury,
+       members fly and pay tax.
+    3. Treasury: Funded by a tax on every member delivery (collected in
+       TravelShip) and by deposits; officers may withdraw from it.
+    4. Chat & Stats: A short history of corp chat (delivered live over the
+       WebSocket hub by the API layer, only to the members' connections,
+       which are authenticated by session token) and corp-wide totals.
+
+    Tuned in the 'corporations' section of 'universe.yaml'.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Corporation roles, from highest to lowest.
+const (
+	RoleFounder = "founder"
+	RoleOfficer = "officer"
+	RoleMember  = "member"
+)
+
+// Corporation limits.
+const (
+	MaxCorpNameLength    = 32
+	MaxCorpChatLength    = 500
+	DefaultCorpChatLines = 50
+)
+
+// Corporation errors.
+var (
+	ErrCorpNotFound    = errors.New("corporation not found")
+	ErrAlreadyInCorp   = errors.New("player already belongs to a corporation")
+	ErrNotInCorp       = errors.New("player does not belong to a corporation")
+	ErrCorpNameTaken   = errors.New("corporation name already taken")
+	ErrInvalidCorpName = errors.New("invalid corporation name")
+	ErrCorpFull        = errors.New("corporation is full")
+	ErrCorpRole        = errors.New("corporation role too low")
+	ErrInvalidRole     = errors.New("invalid corporation role")
+	ErrInvalidTax      = errors.New("tax rate out of range")
+	ErrInvalidMessage  = errors.New("message must not be empty or too long")
+	ErrNotInvited      = errors.New("player has not been invited")
+)
+
+// Corporations holds every corporation by ID.
+// Note: Access must be guarded by DataLock
+var Corporations = make(map[string]*Corporation)
+
+// roleRank orders the roles so permissions can be compared.
+var roleRank = map[string]int{RoleMember: 1, RoleOfficer: 2, RoleFounder: 3}
+
+// GetCorporation returns the corporation a player belongs to, or nil.
+func GetCorporation(p *Player) *Corporation {
+	if p.CorpID == "" {
+		return nil
+	}
+	return Corporations[p.CorpID]
+}
+
+// findMember returns the index of a player in the member list, or -1.
+func (c *Corporation) findMember(playerID string) int {
+	for i, m := range c.Members {
+		if m.PlayerID == playerID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Role returns the role of a player in the corporation ("" = not a member).
+func (c *Corporation) Role(playerID string) string {
+	if idx := c.findMember(playerID); idx != -1 {
+		return c.Members[idx].Role
+	}
+	return ""
+}
+
+// MemberIDs returns the player IDs of every member.
+func (c *Corporation) MemberIDs() []string {
+	ids := []string{}
+	for _, m := range c.Members {
+		ids = append(ids, m.PlayerID)
+	}
+	return ids
+}
+
+// corpWithRole returns the player's corporation if they hold at least 'role' in it.
+func corpWithRole(p *Player, role string) (*Corporation, error) {
+	corp := GetCorporation(p)
+	if corp == nil {
+		return nil, ErrNotInCorp
+	}
+	if roleRank[corp.Role(p.ID)] < roleRank[role] {
+		return nil, ErrCorpRole
+	}
+	return corp, nil
+}
+
+// CreateCorporation founds a new corporation led by the player. The founding fee is
+// paid into the new treasury.
+// Note: Caller must hold DataLock
+func CreateCorporation(p *Player, name string) (*Corporation, error) {
+	if p.CorpID != "" {
+		return nil, ErrAlreadyInCorp
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxCorpNameLength {
+		return nil, ErrInvalidCorpName
+	}
+	for _, c := range Corporations {
+		if strings.EqualFold(c.Name, name) {
+			return nil, ErrCorpNameTaken
+		}
+	}
+	cfg := CurrentUniverse.Corporations
+	if p.Credits < cfg.FoundingFee {
+		return nil, ErrInsufficientCredits
+	}
+
+	now := time.Now().Unix()
+	corp := &Corporation{
+		ID:        fmt.Sprintf("CORP-%d-%d", rand.Intn(99999), time.Now().UnixNano()%1000),
+		Name:      name,
+		TaxRate:   cfg.DefaultTaxRate,
+		Treasury:  cfg.FoundingFee,
+		CreatedAt: now,
+		Members:   []CorpMember{{PlayerID: p.ID, Role: RoleFounder, JoinedAt: now}},
+		Invites:   []string{},
+		Chat:      []CorpChatMessage{},
+	}
+	if cfg.FoundingFee > 0 {
+		p.Credits -= cfg.FoundingFee
+		recordLedger(p, LedgerCorpDeposit, -cfg.FoundingFee, corp.ID, fmt.Sprintf("Founded %s", name))
+	}
+	p.CorpID = corp.ID
+	Corporations[corp.ID] = corp
+	return corp, nil
+}
+
+// JoinCorporation adds the player to a corporation as a member. The player must have been invited.
+// Note: Caller must hold DataLock
+func JoinCorporation(p *Player, corpID string) (*Corporation, error) {
+	if p.CorpID != "" {
+		return nil, ErrAlreadyInCorp
+	}
+	corp := Corporations[corpID]
+	if corp == nil {
+		return nil, ErrCorpNotFound
+	}
+	invite := slices.Index(corp.Invites, p.ID)
+	if invite == -1 {
+		return nil, ErrNotInvited
+	}
+	if limit := CurrentUniverse.Corporations.MaxMembers; limit > 0 && len(corp.Members) >= limit {
+		return nil, ErrCorpFull
+	}
+
+	corp.Invites = slices.Delete(corp.Invites, invite, invite+1)
+	corp.Members = append(corp.Members, CorpMember{PlayerID: p.ID, Role: RoleMember, JoinedAt: time.Now().Unix()})
+	p.CorpID = corp.ID
+	return corp, nil
+}
+
+// InviteMember invites another player to join. Officers and the founder may invite.
+// Note: Caller must hold DataLock
+func InviteMember(p *Player, targetID string) (*Corporation, error) {
+	corp, err := corpWithRole(p, RoleOfficer)
+	if err != nil {
+		return nil, err
+	}
+	target := Players[targetID]
+	if target == nil {
+		return nil, ErrPlayerNotFound
+	}
+	if target.CorpID == corp.ID {
+		return nil, ErrAlreadyInCorp
+	}
+	if !slices.Contains(corp.Invites, targetID) {
+		corp.Invites = append(corp.Invites, targetID)
+	}
+	return corp, nil
+}
+
+// RevokeInvite withdraws a pending invite. Officers and the founder may revoke.
+// Note: Caller must hold DataLock
+func RevokeInvite(p *Player, targetID string) error {
+	corp, err := corpWithRole(p, RoleOfficer)
+	if err != nil {
+		return err
+	}
+	idx := slices.Index(corp.Invites, targetID)
+	if idx == -1 {
+		return ErrNotInvited
+	}
+	corp.Invites = slices.Delete(corp.Invites, idx, idx+1)
+	return nil
+}
+
+// LeaveCorporation removes the player from their corporation. A leaving founder hands the
+// lead to the longest-serving officer (or member). The last member dissolves the corporation
+// and receives what is left in the treasury.
+// Note: Caller must hold DataLock
+func LeaveCorporation(p *Player) error {
+	corp := GetCorporation(p)
+	if corp == nil {
+		return ErrNotInCorp
+	}
+	removeMember(corp, p)
+
+	if len(corp.Members) == 0 {
+		if corp.Treasury > 0 {
+			p.Credits += corp.Treasury
+			recordLedger(p, LedgerCorpWithdraw, corp.Treasury, corp.ID, fmt.Sprintf("Treasury of dissolved %s", corp.Name))
+		}
+		delete(Corporations, corp.ID)
+	}
+	return nil
+}
+
+// removeMember takes a player off the member list and makes sure a founder remains.
+func removeMember(corp *Corporation, p *Player) {
+	idx := corp.findMember(p.ID)
+	wasFounder := corp.Members[idx].Role == RoleFounder
+	corp.Members = append(corp.Members[:idx], corp.Members[idx+1:]...)
+	p.CorpID = ""
+
+	if !wasFounder || len(corp.Members) == 0 {
+		return
+	}
+	// Members are kept in joining order, so the first officer is the longest-serving one
+	heir := 0
+	for i, m := range corp.Members {
+		if m.Role == RoleOfficer {
+			heir = i
+			break
+		}
+	}
+	corp.Members[heir].Role = RoleFounder
+}
+
+// SetCorpRole changes the role of another member. Only the founder may appoint officers;
+// naming another member founder hands over the lead (the old founder becomes an officer).
+// Note: Caller must hold DataLock
+func SetCorpRole(p *Player, targetID, role string) error {
+	corp, err := corpWithRole(p, RoleFounder)
+	if err != nil {
+		return err
+	}
+	if roleRank[role] == 0 {
+		return ErrInvalidRole
+	}
+	idx := corp.findMember(targetID)
+	if idx == -1 {
+		return ErrPlayerNotFound
+	}
+	if targetID == p.ID {
+		return ErrInvalidRole // The founder steps down by naming a successor
+	}
+
+	if role == RoleFounder {
+		corp.Members[corp.findMember(p.ID)].Role = RoleOfficer
+	}
+	corp.Members[idx].Role = role
+	return nil
+}
+
+// KickMember removes another member from the corporation. Officers may remove members;
+// only the founder may remove officers.
+// Note: Caller must hold DataLock
+func KickMember(p *Player, targetID string) error {
+	corp, err := corpWithRole(p, RoleOfficer)
+	if err != nil {
+		return err
+	}
+	target := Players[targetID]
+	if target == nil || corp.findMember(targetID) == -1 {
+		return ErrPlayerNotFound
+	}
+	if roleRank[corp.Role(targetID)] >= roleRank[corp.Role(p.ID)] {
+		return ErrCorpRole
+	}
+	removeMember(corp, target)
+	return nil
+}
+
+// SetCorpTax changes the share of every member delivery paid into the treasury. Founder only.
+// Note: Caller must hold DataLock
+func SetCorpTax(p *Player, rate int) error {
+	corp, err := corpWithRole(p, RoleFounder)
+	if err != nil {
+		return err
+	}
+	if rate < 0 || rate > CurrentUniverse.Corporations.MaxTaxRate {
+		return ErrInvalidTax
+	}
+	corp.TaxRate = rate
+	return nil
+}
+
+// DepositTreasury moves credits from a member's wallet into the treasury.
+// Note: Caller must hold DataLock
+func DepositTreasury(p *Player, amount int) error {
+	corp, err := corpWithRole(p, RoleMember)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if p.Credits < amount {
+		return ErrInsufficientCredits
+	}
+	if err := checkOutgoing(p, amount); err != nil { // Defined in trade.go
+		return err
+	}
+
+	recordOutgoing(p, amount)
+	p.Credits -= amount
+	corp.Treasury += amount
+	recordLedger(p, LedgerCorpDeposit, -amount, corp.ID, fmt.Sprintf("Deposit to %s", corp.Name))
+	return nil
+}
+
+// WithdrawTreasury moves credits from the treasury into an officer's wallet.
+// Note: Caller must hold DataLock
+func WithdrawTreasury(p *Player, amount int) error {
+	corp, err := corpWithRole(p, RoleOfficer)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if corp.Treasury < amount {
+		return ErrInsufficientCredits
+	}
+
+	corp.Treasury -= amount
+	p.Credits += amount
+	recordLedger(p, LedgerCorpWithdraw, amount, corp.ID, fmt.Sprintf("Withdrawal from %s", corp.Name))
+	return nil
+}
+
+// collectCorpTax pays the corporation's share of a trip's payout into the treasury and
+// updates the corp statistics. Called by TravelShip (travel.go). Returns the tax paid.
+func collectCorpTax(p *Player, result *TravelResult) int {
+	corp := GetCorporation(p)
+	if corp == nil || len(result.Delivered) == 0 {
+		return 0
+	}
+	corp.Stats.Deliveries += len(result.Delivered)
+	corp.Stats.Payouts += result.Payout
+	corp.Stats.Distance += result.Distance
+
+	tax := result.Payout * corp.TaxRate / 100
+	if tax <= 0 {
+		return 0
+	}
+	p.Credits -= tax
+	corp.Treasury += tax
+	corp.Stats.TaxCollected += tax
+	recordLedger(p, LedgerCorpTax, -tax, corp.ID, fmt.Sprintf("%d%% tax to %s", corp.TaxRate, corp.Name))
+	return tax
+}
+
+// PostCorpChat appends a message to the corporation's chat history and returns it along
+// with the IDs of the members it must be delivered to.
+// Note: Caller must hold DataLock
+func PostCorpChat(p *Player, text string) (CorpChatMessage, []string, error) {
+	corp := GetCorporation(p)
+	if corp == nil {
+		return CorpChatMessage{}, nil, ErrNotInCorp
+	}
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > MaxCorpChatLength {
+		return CorpChatMessage{}, nil, ErrInvalidMessage
+	}
+
+	msg := CorpChatMessage{Timestamp: time.Now().Unix(), CorpID: corp.ID, PlayerID: p.ID, Text: text}
+	corp.Chat = append(corp.Chat, msg)
+	if limit := CurrentUniverse.Corporations.ChatHistory; len(corp.Chat) > limit {
+		corp.Chat = corp.Chat[len(corp.Chat)-limit:]
+	}
+	return msg, corp.MemberIDs(), nil
+}
+
+// BuildCorpStatus describes a corporation with its members and their combined fleet.
+// Note: Caller must hold DataLock
+func BuildCorpStatus(corp *Corporation) CorpStatus {
+	status := CorpStatus{Corporation: corp}
+	for _, m := range corp.Members {
+		member := Players[m.PlayerID]
+		if member == nil {
+			continue
+		}
+		status.Combined.Credits += member.Credits
+		status.Combined.Reputation += member.Reputation
+		status.Combined.Ships += len(member.Fleet)
+	}
+	return status
+}
+
+// ListCorporations returns every corporation without chat history, sorted by name.
+// Corporations that invited the player are flagged.
+// Note: Caller must hold DataLock
+func ListCorporations(p *Player) []CorpSummary {
+	list := []CorpSummary{}
+	for _, c := range Corporations {
+		list = append(list, CorpSummary{
+			ID: c.ID, Name: c.Name, Members: len(c.Members), TaxRate: c.TaxRate, Treasury: c.Treasury,
+			Invited: slices.Contains(c.Invites, p.ID),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// ValidateCorporations checks the 'corporations' section and fills in defaults.
+func ValidateCorporations(u *Universe) error {
+	cfg := &u.Corporations
+	if cfg.FoundingFee < 0 || cfg.MaxMembers < 0 || cfg.ChatHistory < 0 {
+		return errors.New("corporations: values must not be negative")
+	}
+	if cfg.MaxTaxRate < 0 || cfg.MaxTaxRate > 100 || cfg.DefaultTaxRate < 0 || cfg.DefaultTaxRate > cfg.MaxTaxRate {
+		return errors.New("corporations: tax rates must satisfy 0 <= default_tax_rate <= max_tax_rate <= 100")
+	}
+	if cfg.ChatHistory == 0 {
+		cfg.ChatHistory = DefaultCorpChatLines
+	}
+	return nil
+}
@@ -29,6 +29,11 @@ func InitMarket() {
 			Market.SourceHeat[p.Key][c.Key] = 1.0
 			Market.DestHeat[p.Key][c.Key] = 1.0
 		}
+
+		// Depots start the session fully stocked
+		if p.FuelDepot != nil {
+			Market.FuelStock[p.Key] = p.FuelDepot.Capacity
+		}
 	}
 }
 
@@ -56,6 +61,21 @@ func (m *MarketState) RecordDelivery(destKey, itemKey string, qty int) {
 	m.DestHeat[destKey][itemKey] += impact
 }
 
+// MarketPrice derives the current delivery value of one unit of a commodity at a planet.
+// Mirrors the pricing used by generateCargoJobs: High saturation = Low Price.
+// Note: Caller must hold DataLock
+func MarketPrice(planetKey, itemKey string) int {
+	comm := GetCommodity(itemKey)
+	if comm == nil {
+		return 0
+	}
+	destHeat := Market.DestHeat[planetKey][itemKey]
+	if destHeat <= 0 {
+		destHeat = 1.0
+	}
+	return int(float64(comm.BaseValue) / destHeat)
+}
+
 // MarketTick "Cools down" the economy, simulating consumption and production over time.
 // It pushes all heat values slowly back towards 1.0.
 func MarketTick() {
@@ -85,6 +105,18 @@ func MarketTick() {
 			}
 		}
 	}
+
+	// 3. Restock Fuel Depots (Tankers arrive from the refineries)
+	for _, p := range CurrentUniverse.Planets {
+		if p.FuelDepot == nil {
+			continue
+		}
+		restock := p.FuelDepot.Restock
+		if restock == 0 {
+			restock = p.FuelDepot.Capacity / 20 // Default: 5% of capacity per tick
+		}
+		Market.FuelStock[p.Key] = min(p.FuelDepot.Capacity, Market.FuelStock[p.Key]+restock)
+	}
 }
 
 // ReplenishMarket is the main heartbeat function called by the server loop.
@@ -97,6 +129,9 @@ func ReplenishMarket() []string {
 	DataLock.Lock()
 	defer DataLock.Unlock()
 
+	// 2. Store the post-tick heat map for charting/analysis
+	RecordMarketSnapshot() // Defined in history.go
+
 	updatedPlanets := []string{}
 
 	for i := range CurrentUniverse.Planets {
@@ -135,6 +170,11 @@ func ReplenishMarket() []string {
 		}
 
 		// --- PASSENGER CHECK ---
+		// Only planets with a passenger terminal board travellers.
+		if !HasService(origin.Key, ServicePassengerTerminal) {
+			continue
+		}
+
 		currentPaxCount := 0
 		for _, c := range AvailableContracts[origin.Key] {
 			if c.Type == "passenger" {
@@ -187,6 +227,16 @@ func generateCargoJobs(origin *Planet, count int) {
 			continue
 		}
 
+		// Galactic Events: A strike (or similar) stops the planet from shipping the good
+		if ProductionHalted(origin.Key, comm.Key) {
+			continue
+		}
+
+		// Contraband is only offered at its pickup planets
+		if !CanPickup(comm, origin.Key) {
+			continue
+		}
+
 		// 3. Pick Destination: Must be different from Origin
 		dest := CurrentUniverse.Planets[rand.Intn(len(CurrentUniverse.Planets))]
 		for dest.Key == origin.Key {
@@ -195,16 +245,11 @@ func generateCargoJobs(origin *Planet, count int) {
 
 		// 4. Calculate Economics
 		qty := rand.Intn(21) + 5
-		dist := CalculateDistance(origin.Coordinates, dest.Coordinates)
-		destHeat := Market.DestHeat[dest.Key][comm.Key]
-		priceMod := 1.0 / destHeat // High saturation = Low Price
-
-		basePayout := int(dist)*CurrentUniverse.BalanceConfig.DistancePayoutMult + (comm.BaseValue * qty / 2)
-		finalPayout := int(float64(basePayout) * priceMod)
+		finalPayout := cargoPayout(origin, &dest, comm, qty)
 
 		// 5. Create Contract
 		job := Contract{
-			ID:             fmt.Sprintf("CRG-%d-%d", rand.Intn(99999), time.Now().UnixNano()%1000),
+			ID:             newContractID("CRG"),
 			Type:           "cargo",
 			ItemName:       comm.Name,
 			ItemKey:        comm.Key,
@@ -213,32 +258,76 @@ func generateCargoJobs(origin *Planet, count int) {
 			OriginKey:      origin.Key,
 			DestinationKey: dest.Key,
 			Payout:         finalPayout,
+			Traits:         comm.Traits,
+			Collateral:     CollateralFor(comm, qty),
 		}
+		shapeContract(&job, origin, comm) // Multi-stop or chained (contracts.go)
+		claimForFaction(&job, origin)     // Defined in factions.go
 		AvailableContracts[origin.Key] = append(AvailableContracts[origin.Key], job)
 	}
 }
 
-// generatePassengerJobs creates 'count' new passenger contracts.
+// cargoPayout prices a cargo leg: distance pay plus the value of the goods,
+// scaled by how saturated the destination market is.
+func cargoPayout(from, dest *Planet, comm Commodity, qty int) int {
+	dist := CalculateDistance(from.Coordinates, dest.Coordinates)
+	destHeat := Market.DestHeat[dest.Key][comm.Key]
+	priceMod := 1.0 / destHeat                      // High saturation = Low Price
+	priceMod *= EventPayoutMult(dest.Key, comm.Key) // Famines etc. raise demand
+
+	basePayout := int(dist)*CurrentUniverse.BalanceConfig.DistancePayoutMult + (comm.BaseValue * qty / 2)
+	return int(float64(basePayout) * priceMod)
+}
+
+// newContractID returns a runtime contract ID with the given prefix (e.g., "CRG-1024-55").
+func newContractID(prefix string) string {
+	return fmt.Sprintf("%s-%d-%d", prefix, rand.Intn(99999), time.Now().UnixNano()%1000)
+}
+
+// generatePassengerJobs creates 'count' new passenger contracts of random classes (passengers.go).
+// Passengers only travel between planets that have a passenger terminal.
 func generatePassengerJobs(origin *Planet, count int) {
+	terminals := []Planet{}
+	for _, p := range CurrentUniverse.Planets {
+		if p.Key != origin.Key && HasService(p.Key, ServicePassengerTerminal) {
+			terminals = append(terminals, p)
+		}
+	}
+	if len(terminals) == 0 {
+		return
+	}
+
 	for i := 0; i < count; i++ {
-		dest := CurrentUniverse.Planets[rand.Intn(len(CurrentUniverse.Planets))]
-		for dest.Key == origin.Key {
-			dest = CurrentUniverse.Planets[rand.Intn(len(CurrentUniverse.Planets))]
+		class := pickPassengerClass(origin.Key)
+		if class == nil {
+			return
 		}
+		dest := terminals[rand.Intn(len(terminals))]
 
+		qty := partySize(*class)
 		dist := CalculateDistance(origin.Coordinates, dest.Coordinates)
-		payout := int(dist)*15 + CurrentUniverse.PassengerConfig.BaseTicketPrice
+		payout := PassengerFare(*class, dist, qty)
+		payout = int(float64(payout) * EventPayoutMult(dest.Key, "passenger"))
+
+		mass := class.Mass
+		if mass == 0 {
+			mass = CurrentUniverse.PassengerConfig.MassPerPassenger
+		}
 
 		job := Contract{
-			ID:             fmt.Sprintf("PAX-%d-%d", rand.Intn(99999), time.Now().UnixNano()%1000),
-			Type:           "passenger",
-			ItemName:       "Passenger",
-			ItemKey:        "passenger",
-			Quantity:       1,
-			MassPerUnit:    CurrentUniverse.PassengerConfig.MassPerPassenger,
-			OriginKey:      origin.Key,
-			DestinationKey: dest.Key,
-			Payout:         payout,
+			ID:              newContractID("PAX"),
+			Type:            "passenger",
+			ItemName:        class.Name,
+			ItemKey:         "passenger",
+			Quantity:        qty,
+			MassPerUnit:     mass,
+			OriginKey:       origin.Key,
+			DestinationKey:  dest.Key,
+			Payout:          payout,
+			Class:           class.Key,
+			ComfortRequired: max(class.ComfortRequirement, CurrentUniverse.PassengerConfig.ComfortRequirement),
+			BonusReputation: class.BonusReputation,
+			Faction:         origin.Faction,
 		}
 		AvailableContracts[origin.Key] = append(AvailableContracts[origin.Key], job)
 	}
@@ -0,0 +1,144 @@
+/*
+Package game
+File: escrow.go
+Description:
+    Collateral for valuable cargo contracts.
+
+    Cargo worth at least 'collateral_threshold' (BaseValue * Quantity) demands
+    a deposit of 'collateral_rate' of its value. The deposit is taken from the
+    wallet on accept and held in escrow while the cargo is aboard:
+    - Delivered:           refunded to the wallet.
+    - Dropped or expired:  forfeited.
+    - Lost or seized:      forfeited (the cargo never arrived).
+
+    Contracts with collateral must be delivered within 'contract_term'
+    seconds; ExpireContracts sweeps overdue ones on the heartbeat.
+    Every movement is written to the player's ledger (ledger.go).
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CollateralFor returns the deposit demanded for 'qty' units of a commodity (0 = none).
+func CollateralFor(comm Commodity, qty int) int {
+	cfg := CurrentUniverse.BalanceConfig
+	value := comm.BaseValue * qty
+	if cfg.CollateralRate <= 0 || value < cfg.CollateralThreshold {
+		return 0
+	}
+	return int(float64(value) * cfg.CollateralRate)
+}
+
+// holdCollateral takes the contract's deposit from the wallet into escrow and starts
+// its delivery deadline. Fails without side effects if the player can't afford it.
+func holdCollateral(p *Player, c *Contract) error {
+	if c.Collateral <= 0 {
+		return nil
+	}
+	if p.Credits < c.Collateral {
+		return ErrInsufficientCredits
+	}
+
+	p.Credits -= c.Collateral
+	p.Escrow += c.Collateral
+	if term := CurrentUniverse.BalanceConfig.ContractTerm; term > 0 {
+		c.Deadline = time.Now().Unix() + int64(term)
+	}
+	recordLedger(p, LedgerEscrowHold, -c.Collateral, c.ID, fmt.Sprintf("Collateral for %d %s", c.Quantity, c.ItemName))
+	return nil
+}
+
+// releaseCollateral refunds the deposit of a delivered contract. Returns the amount refunded.
+func releaseCollateral(p *Player, c Contract) int {
+	if c.Collateral <= 0 {
+		return 0
+	}
+	p.Credits += c.Collateral
+	p.Escrow -= c.Collateral
+	recordLedger(p, LedgerEscrowRefund, c.Collateral, c.ID, fmt.Sprintf("Collateral returned for %d %s", c.Quantity, c.ItemName))
+	return c.Collateral
+}
+
+// forfeitCollateral gives up the deposit of a failed contract. The wallet is unaffected;
+// the credits already left it on accept.
+func forfeitCollateral(p *Player, c Contract, reason string) {
+	if c.Collateral <= 0 {
+		return
+	}
+	p.Escrow -= c.Collateral
+	recordLedger(p, LedgerEscrowForfeit, 0, c.ID, fmt.Sprintf("Collateral of %d forfeited: %s", c.Collateral, reason))
+}
+
+// DropContract abandons a contract aboard the ship.
+// Costs reputation (see ReputationPerDrop) and forfeits any collateral.
+// Note: Caller must hold DataLock
+func DropContract(p *Player, ship *Ship, contractID string) (Contract, error) {
+	idx := findContract(ship.ActiveContracts, contractID)
+	if idx == -1 {
+		return Contract{}, ErrContractNotFound
+	}
+	c := ship.ActiveContracts[idx]
+
+	p.Reputation -= ReputationPerDrop
+	penalizeStanding(p, c) // Defined in factions.go
+	forfeitCollateral(p, c, "contract dropped")
+	if c.PostedBy != "" {
+		failPosting(c, "dropped by the hauler") // Defined in postings.go
+	}
+	ship.ActiveContracts = append(ship.ActiveContracts[:idx], ship.ActiveContracts[idx+1:]...)
+	return c, nil
+}
+
+// ExpireContracts removes overdue contracts from every ship (players and NPC traders)
+// and overdue player postings from the job boards, forfeiting their collateral as if they were dropped.
+// Called by the heartbeat in main.go. Returns the number of contracts expired.
+func ExpireContracts() int {
+	DataLock.Lock()
+	defer DataLock.Unlock()
+
+	wallets := []*Player{}
+	for _, p := range Players {
+		wallets = append(wallets, p)
+	}
+	for _, t := range Traders {
+		wallets = append(wallets, t.Wallet)
+	}
+
+	now := time.Now().Unix()
+	expired := 0
+	for _, p := range wallets {
+		for _, ship := range p.Fleet {
+			remaining := []Contract{}
+			for _, c := range ship.ActiveContracts {
+				if c.Deadline == 0 || now <= c.Deadline {
+					remaining = append(remaining, c)
+					continue
+				}
+				p.Reputation -= ReputationPerDrop
+				penalizeStanding(p, c)
+				forfeitCollateral(p, c, "deadline missed")
+				if c.PostedBy != "" {
+					failPosting(c, "deadline missed by the hauler")
+				}
+				logRoute(ship, "expire", 0, fmt.Sprintf("Contract %s expired, collateral of %d forfeited", c.ID, c.Collateral))
+				expired++
+			}
+			ship.ActiveContracts = remaining
+		}
+	}
+	return expired + expirePostings() // Defined in postings.go
+}
+
+// ValidateEscrow checks the collateral settings in 'game_balance'.
+func ValidateEscrow(u *Universe) error {
+	cfg := u.BalanceConfig
+	if cfg.CollateralRate < 0 || cfg.CollateralThreshold < 0 || cfg.ContractTerm < 0 {
+		return errors.New("game_balance: collateral values must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+/*
+Package game
+File: events.go
+Description:
+    Runs random galactic events (famines, solar storms, strikes...).
+
+    Events are defined in 'universe.yaml'. On every economy tick, running
+    events count down and expire, and new ones may strike a planet. While
+    active, their effects are consulted by:
+    - Contract generation (economy.go): demand raises payouts, strikes halt production.
+    - Fuel burn (mechanics.go): storms raise the burn of flights passing near the planet.
+
+    The heartbeat in main.go broadcasts every start and end over the hub.
+*/
+
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// Event effect types.
+const (
+	EffectDemand         = "demand"          // Multiplies payouts of contracts delivered to the planet
+	EffectHaltProduction = "halt_production" // The planet generates no cargo contracts for the item
+	EffectBurn           = "burn"            // Multiplies the burn of flights passing within Radius of the planet
+)
+
+// DefaultMaxActiveEvents is used when 'max_active_events' is missing from the YAML.
+const DefaultMaxActiveEvents = 2
+
+// TickEvents advances all running events by one economy tick and rolls for new ones.
+// An event definition never runs twice at the same time.
+// Returns the events that started and ended during this tick.
+func TickEvents() (started, ended []ActiveEvent) {
+	DataLock.Lock()
+	defer DataLock.Unlock()
+
+	// 1. Count down and expire
+	running := []ActiveEvent{}
+	for _, e := range ActiveEvents {
+		e.RemainingTicks--
+		if e.RemainingTicks <= 0 {
+			ended = append(ended, e)
+			continue
+		}
+		running = append(running, e)
+	}
+	ActiveEvents = running
+
+	// 2. Roll for new events
+	limit := CurrentUniverse.BalanceConfig.MaxActiveEvents
+	if limit <= 0 {
+		limit = DefaultMaxActiveEvents
+	}
+	for _, def := range CurrentUniverse.Events {
+		if len(ActiveEvents) >= limit {
+			break
+		}
+		if eventRunning(def.Key) || rand.Float64() >= def.Chance {
+			continue
+		}
+		e := startEvent(def)
+		if e.PlanetKey == "" {
+			continue
+		}
+		ActiveEvents = append(ActiveEvents, e)
+		started = append(started, e)
+	}
+	return started, ended
+}
+
+// startEvent creates a running instance of a definition at a random eligible planet.
+func startEvent(def EventDefinition) ActiveEvent {
+	candidates := def.Planets
+	if len(candidates) == 0 {
+		for _, p := range CurrentUniverse.Planets {
+			candidates = append(candidates, p.Key)
+		}
+	}
+	if len(candidates) == 0 {
+		return ActiveEvent{}
+	}
+
+	duration := def.MinDuration
+	if def.MaxDuration > def.MinDuration {
+		duration += rand.Intn(def.MaxDuration - def.MinDuration + 1)
+	}
+	if duration < 1 {
+		duration = 1
+	}
+
+	return ActiveEvent{
+		ID:             fmt.Sprintf("EVT-%d-%d", rand.Intn(99999), time.Now().UnixNano()%1000),
+		Key:            def.Key,
+		Name:           def.Name,
+		Description:    def.Description,
+		PlanetKey:      candidates[rand.Intn(len(candidates))],
+		Effects:        def.Effects,
+		StartedAt:      time.Now().Unix(),
+		Duration:       duration,
+		RemainingTicks: duration,
+	}
+}
+
+// eventRunning reports whether an instance of the definition is active.
+func eventRunning(key string) bool {
+	for _, e := range ActiveEvents {
+		if e.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// EventPayoutMult returns the combined demand multiplier for delivering an item to a planet.
+// Note: Caller must hold DataLock
+func EventPayoutMult(planetKey, itemKey string) float64 {
+	mult := 1.0
+	for _, e := range ActiveEvents {
+		if e.PlanetKey != planetKey {
+			continue
+		}
+		for _, fx := range e.Effects {
+			if fx.Type == EffectDemand && (fx.Item == "" || fx.Item == itemKey) {
+				mult *= fx.Mult
+			}
+		}
+	}
+	return mult
+}
+
+// ProductionHalted reports whether an event stops a planet from offering cargo of an item.
+// Note: Caller must hold DataLock
+func ProductionHalted(planetKey, itemKey string) bool {
+	for _, e := range ActiveEvents {
+		if e.PlanetKey != planetKey {
+			continue
+		}
+		for _, fx := range e.Effects {
+			if fx.Type == EffectHaltProduction && (fx.Item == "" || fx.Item == itemKey) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EventBurnMult returns the combined burn multiplier for a flight between two planets.
+// A storm counts if any point of the route comes within its radius.
+// Note: Caller must hold DataLock
+func EventBurnMult(originKey, destKey string) float64 {
+	origin, dest := GetPlanet(originKey), GetPlanet(destKey)
+	if origin == nil || dest == nil {
+		return 1.0
+	}
+
+	mult := 1.0
+	for _, e := range ActiveEvents {
+		center := GetPlanet(e.PlanetKey)
+		if center == nil {
+			continue
+		}
+		for _, fx := range e.Effects {
+			if fx.Type == EffectBurn && DistanceToRoute(center.Coordinates, origin.Coordinates, dest.Coordinates) <= fx.Radius {
+				mult *= fx.Mult
+			}
+		}
+	}
+	return mult
+}
+
+// ValidateEvents checks the 'galactic_events' definitions.
+func ValidateEvents(u *Universe) error {
+	planets := make(map[string]bool)
+	terminals := make(map[string]bool)
+	for _, p := range u.Planets {
+		planets[p.Key] = true
+		terminals[p.Key] = slices.Contains(p.Services, ServicePassengerTerminal)
+	}
+
+	keys := make(map[string]bool)
+	for _, def := range u.Events {
+		if def.Key == "" || keys[def.Key] {
+			return fmt.Errorf("event %q: missing or duplicate key", def.Key)
+		}
+		keys[def.Key] = true
+
+		if def.Chance < 0 || def.Chance > 1 {
+			return fmt.Errorf("event %q: chance must be between 0 and 1", def.Key)
+		}
+		if def.MinDuration < 1 || def.MaxDuration < def.MinDuration {
+			return fmt.Errorf("event %q: invalid duration range", def.Key)
+		}
+		for _, k := range def.Planets {
+			if !planets[k] {
+				return fmt.Errorf("event %q: unknown planet %q", def.Key, k)
+			}
+		}
+		for _, fx := range def.Effects {
+			switch fx.Type {
+			case EffectDemand, EffectBurn:
+				if fx.Mult <= 0 {
+					return fmt.Errorf("event %q: %s effect needs a positive mult", def.Key, fx.Type)
+				}
+				// Passengers only fly to terminals, so passenger demand elsewhere would never apply
+				if fx.Type == EffectDemand && fx.Item == "passenger" {
+					for _, k := range def.Planets {
+						if !terminals[k] {
+							return fmt.Errorf("event %q: planet %q has no passenger terminal", def.Key, k)
+						}
+					}
+				}
+			case EffectHaltProduction:
+			default:
+				return fmt.Errorf("event %q: unknown effect %q", def.Key, fx.Type)
+			}
+		}
+	}
+	return nil
+}
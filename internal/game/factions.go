@@ -0,0 +1,227 @@
+/*
+Package game
+File: factions.go
+Description:
+    Factions, their territory and every player's standing with them.
+
+    Planets may belong to a faction (the 'faction' key of a planet). Jobs
+    issued there carry the faction, and how they end decides the standing:
+    - Delivered: Standing rises with the issuing faction and falls with its rivals.
+    - Dropped, expired or lost: Standing with the issuing faction falls.
+
+    Standing pays off inside the faction's territory: contract payouts rise
+    and fuel gets cheaper (or the reverse for disliked captains). Some jobs
+    and shipyard modules are reserved for captains above a minimum standing.
+
+    Tuned in the 'faction_config' section of 'universe.yaml'.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// MaxStandingModifier caps the effect of standing on prices, so fuel never becomes free
+// and payouts never turn negative, even with an unlimited max_standing.
+const MaxStandingModifier = 0.9
+
+// Faction errors.
+var (
+	ErrStandingTooLow = errors.New("faction standing too low")
+)
+
+// GetFaction returns the faction with the given key, or nil.
+func GetFaction(key string) *Faction {
+	for i := range CurrentUniverse.FactionConfig.Factions {
+		if CurrentUniverse.FactionConfig.Factions[i].Key == key {
+			return &CurrentUniverse.FactionConfig.Factions[i]
+		}
+	}
+	return nil
+}
+
+// FactionOf returns the key of the faction that controls a planet ("" = independent).
+func FactionOf(planetKey string) string {
+	if planet := GetPlanet(planetKey); planet != nil {
+		return planet.Faction
+	}
+	return ""
+}
+
+// adjustStanding changes a player's standing with a faction, clamped to +/- MaxStanding.
+func adjustStanding(p *Player, factionKey string, delta int) {
+	if factionKey == "" || delta == 0 {
+		return
+	}
+	if p.Standing == nil {
+		p.Standing = make(map[string]int)
+	}
+	limit := CurrentUniverse.FactionConfig.MaxStanding
+	standing := p.Standing[factionKey] + delta
+	if limit > 0 {
+		standing = max(-limit, min(standing, limit))
+	}
+	p.Standing[factionKey] = standing
+}
+
+// rewardStanding credits a delivered contract to the issuing faction; its rivals take note.
+func rewardStanding(p *Player, c Contract) {
+	faction := GetFaction(c.Faction)
+	if faction == nil {
+		return
+	}
+	cfg := CurrentUniverse.FactionConfig
+	adjustStanding(p, faction.Key, cfg.StandingPerDelivery)
+	for _, rival := range faction.Rivals {
+		adjustStanding(p, rival, -cfg.RivalPenalty)
+	}
+}
+
+// penalizeStanding charges an abandoned contract (dropped, expired or lost) to the issuing faction.
+func penalizeStanding(p *Player, c Contract) {
+	adjustStanding(p, c.Faction, -CurrentUniverse.FactionConfig.StandingPerDrop)
+}
+
+// standingModifier returns the price modifier a player's standing earns with a faction
+// (e.g., 0.1 = payouts +10%, fuel -10%), clamped to +/- MaxStandingModifier.
+// Independent planets always return 0.
+func standingModifier(p *Player, factionKey string) float64 {
+	if factionKey == "" {
+		return 0
+	}
+	mod := float64(p.Standing[factionKey]) * CurrentUniverse.FactionConfig.PricePerStanding
+	return math.Max(-MaxStandingModifier, math.Min(MaxStandingModifier, mod))
+}
+
+// scalePayout multiplies the payout of a contract, stop by stop for multi-stop contracts
+// so the stops still add up to the total.
+func scalePayout(c *Contract, mult float64) {
+	if len(c.Stops) == 0 {
+		c.Payout = int(float64(c.Payout) * mult)
+		return
+	}
+	stops := append([]ContractStop(nil), c.Stops...)
+	c.Payout = 0
+	for i := range stops {
+		stops[i].Payout = int(float64(stops[i].Payout) * mult)
+		c.Payout += stops[i].Payout
+	}
+	c.Stops = stops
+}
+
+// PriceForPlayer returns a job board contract with the payout the player would be offered,
+// based on their standing with the issuing faction. Player postings keep their escrowed payout.
+func PriceForPlayer(p *Player, c Contract) Contract {
+	if c.PostedBy != "" {
+		return c
+	}
+	if mod := standingModifier(p, c.Faction); mod != 0 {
+		scalePayout(&c, 1.0+mod)
+	}
+	return c
+}
+
+// checkStanding rejects faction-only contracts for players below the required standing.
+func checkStanding(p *Player, c Contract) error {
+	if c.MinStanding > 0 && p.Standing[c.Faction] < c.MinStanding {
+		return ErrStandingTooLow
+	}
+	return nil
+}
+
+// claimForFaction marks a freshly generated cargo contract as issued by the faction that
+// controls its origin, and rolls whether it is reserved for the faction's trusted captains.
+// Called by generateCargoJobs (economy.go).
+func claimForFaction(job *Contract, origin *Planet) {
+	faction := GetFaction(origin.Faction)
+	if faction == nil {
+		return
+	}
+	job.Faction = faction.Key
+	if rand.Float64() < faction.ExclusiveChance {
+		job.MinStanding = faction.ExclusiveStanding
+		scalePayout(job, 1.0+faction.ExclusiveBonus)
+	}
+}
+
+// BuildFactionStatus describes every faction from the player's point of view.
+// Note: Caller must hold DataLock
+func BuildFactionStatus(p *Player) []FactionStatus {
+	status := []FactionStatus{}
+	for _, f := range CurrentUniverse.FactionConfig.Factions {
+		s := FactionStatus{
+			Faction:   f,
+			Territory: []string{},
+			Standing:  p.Standing[f.Key],
+		}
+		for _, planet := range CurrentUniverse.Planets {
+			if planet.Faction == f.Key {
+				s.Territory = append(s.Territory, planet.Key)
+			}
+		}
+		mod := standingModifier(p, f.Key)
+		s.PayoutMult = math.Round((1.0+mod)*1000) / 1000
+		s.FuelMult = math.Round((1.0-mod)*1000) / 1000
+		s.Exclusive = f.ExclusiveStanding > 0 && s.Standing >= f.ExclusiveStanding
+		status = append(status, s)
+	}
+	return status
+}
+
+// ValidateFactions checks the 'faction_config' section and the faction keys of planets and modules.
+func ValidateFactions(u *Universe) error {
+	cfg := u.FactionConfig
+	if cfg.StandingPerDelivery < 0 || cfg.StandingPerDrop < 0 || cfg.RivalPenalty < 0 || cfg.MaxStanding < 0 || cfg.PricePerStanding < 0 {
+		return errors.New("faction_config: values must not be negative")
+	}
+	// Standing must never push a price to zero or below
+	if cfg.MaxStanding > 0 && float64(cfg.MaxStanding)*cfg.PricePerStanding >= 1 {
+		return errors.New("faction_config: max_standing * price_per_standing must stay below 1")
+	}
+
+	known := make(map[string]bool)
+	for _, f := range cfg.Factions {
+		if f.Key == "" {
+			return fmt.Errorf("faction %q: missing key", f.Name)
+		}
+		if known[f.Key] {
+			return fmt.Errorf("faction %q: duplicate key", f.Key)
+		}
+		known[f.Key] = true
+		if f.ExclusiveChance < 0 || f.ExclusiveChance > 1 {
+			return fmt.Errorf("faction %q: exclusive_chance must be between 0 and 1", f.Key)
+		}
+		if f.ExclusiveChance > 0 && f.ExclusiveStanding <= 0 {
+			return fmt.Errorf("faction %q: exclusive_chance set without a positive exclusive_standing", f.Key)
+		}
+		if f.ExclusiveBonus < 0 {
+			return fmt.Errorf("faction %q: exclusive_bonus must not be negative", f.Key)
+		}
+	}
+	for _, f := range cfg.Factions {
+		for _, rival := range f.Rivals {
+			if !known[rival] || rival == f.Key {
+				return fmt.Errorf("faction %q: invalid rival %q", f.Key, rival)
+			}
+		}
+	}
+
+	for _, p := range u.Planets {
+		if p.Faction != "" && !known[p.Faction] {
+			return fmt.Errorf("planet %q: unknown faction %q", p.Key, p.Faction)
+		}
+	}
+	for _, m := range u.ShipModules {
+		if m.Faction != "" && !known[m.Faction] {
+			return fmt.Errorf("module %q: unknown faction %q", m.Key, m.Faction)
+		}
+		if m.Faction == "" && m.MinStanding != 0 {
+			return fmt.Errorf("module %q: min_standing set without faction", m.Key)
+		}
+	}
+	return nil
+}
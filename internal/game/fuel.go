@@ -0,0 +1,167 @@
+/*
+Package game
+File: fuel.go
+Description:
+    Handles fuel pricing and refueling rules.
+
+    Fuel is stored on the ship in atomic units, while prices in 'universe.yaml'
+    are quoted per 1.00 fuel (100 atomic units). All conversions between the
+    two live here so every endpoint rounds the same way.
+
+    Fuel is sold by planetary depots. Each depot has its own price multiplier
+    and a limited stock, and the local price reacts to the "item_fuel" market.
+*/
+
+package game
+
+import (
+	"errors"
+	"math"
+)
+
+// FuelUnitScale is the number of atomic fuel units in 1.00 fuel (the unit FuelCostPerUnit is priced in).
+const FuelUnitScale = 100
+
+// FuelItemKey is the commodity whose market heat drives depot fuel prices.
+const FuelItemKey = "item_fuel"
+
+// Refuel validation errors.
+var (
+	ErrTankFull         = errors.New("tank is already full")
+	ErrInvalidRefuel    = errors.New("specify either an amount or a budget, not both")
+	ErrExceedsTank      = errors.New("requested fuel exceeds tank capacity")
+	ErrBudgetTooSmall   = errors.New("budget cannot buy any fuel")
+	ErrNegativeQuantity = errors.New("amount and budget must not be negative")
+	ErrNoFuelDepot      = errors.New("no fuel depot at this location")
+	ErrOutOfStock       = errors.New("fuel depot is out of stock")
+	ErrExceedsStock     = errors.New("requested fuel exceeds depot stock")
+)
+
+// GetFuelMarket returns the current fuel price and stock at a planet.
+// Price Formula: FuelCostPerUnit * PriceMult * Production * (SourceHeat / DestHeat) * Shortage
+//   - Production: Planets producing "item_fuel" sell 20% cheaper.
+//   - Heat: Hauling fuel cells away raises the price, delivering them lowers it.
+//   - Shortage: Up to +50% as the depot runs empty.
+//
+// Note: Caller must hold DataLock
+func GetFuelMarket(planetKey string) FuelMarket {
+	info := FuelMarket{PlanetKey: planetKey}
+
+	planet := GetPlanet(planetKey)
+	if planet == nil || planet.FuelDepot == nil || !HasService(planetKey, ServiceFuelDepot) {
+		return info
+	}
+	depot := planet.FuelDepot
+
+	price := float64(CurrentUniverse.BalanceConfig.FuelCostPerUnit)
+	if depot.PriceMult > 0 {
+		price *= depot.PriceMult
+	}
+
+	for _, prod := range planet.Production {
+		if prod == FuelItemKey {
+			price *= 0.8
+			break
+		}
+	}
+
+	if source := Market.SourceHeat[planetKey][FuelItemKey]; source > 0 {
+		price *= source
+	}
+	if dest := Market.DestHeat[planetKey][FuelItemKey]; dest > 0 {
+		price /= dest
+	}
+
+	info.Stock = Market.FuelStock[planetKey]
+	info.Capacity = depot.Capacity
+	if depot.Capacity > 0 {
+		shortage := 1.0 - float64(info.Stock)/float64(depot.Capacity)
+		price *= 1.0 + 0.5*shortage
+	}
+
+	// Round to hundredths so the displayed price is the price actually charged
+	info.PricePerUnit = math.Round(price*100) / 100
+	info.Available = info.Stock > 0
+	return info
+}
+
+// FuelCost returns the exact price of 'amount' atomic fuel units at 'pricePerUnit' credits per 1.00 fuel.
+// Any fraction of a credit is rounded UP, so no quantity of fuel is ever free.
+func FuelCost(amount int64, pricePerUnit float64) int {
+	// Work in hundredths of a credit to avoid float drift on exact multiples
+	priceCents := int64(math.Round(pricePerUnit * 100))
+	return int((amount*priceCents + FuelUnitScale*100 - 1) / (FuelUnitScale * 100))
+}
+
+// FuelForBudget returns the largest amount of atomic fuel units whose FuelCost fits in 'budget'.
+func FuelForBudget(budget int, pricePerUnit float64) int64 {
+	priceCents := int64(math.Round(pricePerUnit * 100))
+	if priceCents <= 0 {
+		return math.MaxInt64 // Free fuel: Only the tank and depot limit the amount
+	}
+	return int64(budget) * FuelUnitScale * 100 / priceCents
+}
+
+// PlanRefuel resolves a refuel request for one of the player's ships into an exact quote without modifying it.
+// Exactly one mode applies:
+//   - amount > 0: Buy exactly 'amount' units (must fit in the tank and the depot stock).
+//   - budget > 0: Buy as much as 'budget' credits allow (capped at a full tank / depot stock).
+//   - neither:    Fill the tank to MaxFuel (or as far as the depot stock allows).
+//
+// Inside faction territory the depot price is adjusted by the player's standing (factions.go).
+//
+// Note: Caller must hold DataLock
+func PlanRefuel(p *Player, ship *Ship, amount int64, budget int) (RefuelQuote, error) {
+	if amount < 0 || budget < 0 {
+		return RefuelQuote{}, ErrNegativeQuantity
+	}
+	if amount > 0 && budget > 0 {
+		return RefuelQuote{}, ErrInvalidRefuel
+	}
+
+	depot := GetFuelMarket(ship.LocationKey)
+	if depot.Capacity == 0 {
+		return RefuelQuote{}, ErrNoFuelDepot
+	}
+	if !depot.Available {
+		return RefuelQuote{}, ErrOutOfStock
+	}
+	price := depot.PricePerUnit
+	if mod := standingModifier(p, FactionOf(ship.LocationKey)); mod != 0 {
+		price = math.Round(price*(1.0-mod)*100) / 100
+	}
+
+	space := ship.Effective.MaxFuel - ship.Fuel
+	if space <= 0 {
+		return RefuelQuote{}, ErrTankFull
+	}
+
+	switch {
+	case amount > 0:
+		if amount > space {
+			return RefuelQuote{}, ErrExceedsTank
+		}
+		if amount > depot.Stock {
+			return RefuelQuote{}, ErrExceedsStock
+		}
+	case budget > 0:
+		amount = min(FuelForBudget(budget, price), space, depot.Stock)
+		if amount <= 0 {
+			return RefuelQuote{}, ErrBudgetTooSmall
+		}
+	default:
+		amount = min(space, depot.Stock)
+	}
+
+	cost := FuelCost(amount, price)
+	fuelAfter := ship.Fuel + amount
+
+	return RefuelQuote{
+		Amount:       amount,
+		Cost:         cost,
+		PricePerUnit: price,
+		FuelAfter:    fuelAfter,
+		BurnAfter:    CalculateBurnWithFuel(ship, fuelAfter),
+		CanAfford:    p.Credits >= cost,
+	}, nil
+}
@@ -0,0 +1,165 @@
+/*
+Package game
+File: history.go
+Description:
+    Records the Market Heat over time so the economy can be charted and analysed.
+
+    Every economy tick a MarketSnapshot of all planets/commodities is pushed into
+    a fixed-size ring buffer. Once the buffer is full, the oldest tick is overwritten,
+    so memory usage stays bounded no matter how long the server runs.
+*/
+
+package game
+
+import "time"
+
+// DefaultMarketHistorySize is used when 'market_history_size' is missing from the YAML.
+// At one tick per minute this keeps 24 hours of history.
+const DefaultMarketHistorySize = 1440
+
+// MarketHistoryBuffer is a ring buffer of MarketSnapshots (oldest -> newest).
+// Note: Caller must hold DataLock for every method.
+type MarketHistoryBuffer struct {
+	snapshots []MarketSnapshot
+	next      int  // Index the next snapshot will be written to
+	full      bool // True once the buffer has wrapped around at least once
+}
+
+// Resize changes the capacity of the buffer, keeping the most recent snapshots.
+// Called on (re)load so designers can tune the history length via YAML.
+func (h *MarketHistoryBuffer) Resize(size int) {
+	if size <= 0 {
+		size = DefaultMarketHistorySize
+	}
+	if size == len(h.snapshots) {
+		return
+	}
+
+	ordered := h.ordered()
+	if len(ordered) > size {
+		ordered = ordered[len(ordered)-size:]
+	}
+
+	h.snapshots = make([]MarketSnapshot, size)
+	copy(h.snapshots, ordered)
+	h.next = len(ordered) % size
+	h.full = len(ordered) == size
+}
+
+// Push appends a snapshot, overwriting the oldest one if the buffer is full.
+func (h *MarketHistoryBuffer) Push(snap MarketSnapshot) {
+	if len(h.snapshots) == 0 {
+		h.Resize(DefaultMarketHistorySize)
+	}
+	h.snapshots[h.next] = snap
+	h.next = (h.next + 1) % len(h.snapshots)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// ordered returns the stored snapshots in chronological order.
+func (h *MarketHistoryBuffer) ordered() []MarketSnapshot {
+	if !h.full {
+		return append([]MarketSnapshot{}, h.snapshots[:h.next]...)
+	}
+	out := make([]MarketSnapshot, 0, len(h.snapshots))
+	out = append(out, h.snapshots[h.next:]...)
+	return append(out, h.snapshots[:h.next]...)
+}
+
+// RecordMarketSnapshot captures the current heat map into MarketHistory.
+// Note: Caller must hold DataLock
+func RecordMarketSnapshot() {
+	snap := MarketSnapshot{
+		Timestamp: time.Now().Unix(),
+		Points:    make(map[string]map[string]MarketSample),
+	}
+
+	for _, p := range CurrentUniverse.Planets {
+		snap.Points[p.Key] = make(map[string]MarketSample)
+		for _, c := range CurrentUniverse.Commodities {
+			snap.Points[p.Key][c.Key] = MarketSample{
+				Timestamp:  snap.Timestamp,
+				SourceHeat: Market.SourceHeat[p.Key][c.Key],
+				DestHeat:   Market.DestHeat[p.Key][c.Key],
+				Price:      MarketPrice(p.Key, c.Key),
+			}
+		}
+	}
+
+	MarketHistory.Push(snap)
+}
+
+// QueryMarketHistory returns the raw samples for a Planet/Commodity pair
+// recorded within [from, to] (Unix seconds). A zero 'to' means "up to now".
+// Note: Caller must hold DataLock (Read Lock is sufficient)
+func QueryMarketHistory(planetKey, itemKey string, from, to int64) []MarketSample {
+	samples := []MarketSample{}
+	for _, snap := range MarketHistory.ordered() {
+		if snap.Timestamp < from || (to > 0 && snap.Timestamp > to) {
+			continue
+		}
+		if sample, ok := snap.Points[planetKey][itemKey]; ok {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// DownsampleMarketHistory groups samples into fixed-width time buckets (in seconds)
+// and summarises each bucket. Empty buckets are omitted.
+func DownsampleMarketHistory(samples []MarketSample, bucket int64) []MarketAggregate {
+	aggregates := []MarketAggregate{}
+	if bucket <= 0 {
+		return aggregates
+	}
+
+	var current *MarketAggregate
+	var priceSum float64
+	for _, s := range samples {
+		start := s.Timestamp - s.Timestamp%bucket
+
+		// Close the previous bucket when we cross into a new one
+		if current == nil || current.Start != start {
+			if current != nil {
+				finishAggregate(current, priceSum)
+				aggregates = append(aggregates, *current)
+			}
+			current = &MarketAggregate{
+				Start:     start,
+				End:       start + bucket,
+				OpenPrice: s.Price,
+				MinPrice:  s.Price,
+				MaxPrice:  s.Price,
+			}
+			priceSum = 0
+		}
+
+		current.Samples++
+		current.AvgSourceHeat += s.SourceHeat
+		current.AvgDestHeat += s.DestHeat
+		current.ClosePrice = s.Price
+		if s.Price < current.MinPrice {
+			current.MinPrice = s.Price
+		}
+		if s.Price > current.MaxPrice {
+			current.MaxPrice = s.Price
+		}
+		priceSum += float64(s.Price)
+	}
+
+	if current != nil {
+		finishAggregate(current, priceSum)
+		aggregates = append(aggregates, *current)
+	}
+	return aggregates
+}
+
+// finishAggregate converts the running sums of a bucket into averages.
+func finishAggregate(a *MarketAggregate, priceSum float64) {
+	n := float64(a.Samples)
+	a.AvgSourceHeat /= n
+	a.AvgDestHeat /= n
+	a.AvgPrice = priceSum / n
+}
@@ -0,0 +1,114 @@
+/*
+Package game
+File: ledger.go
+Description:
+    Records the credit movements of a player.
+
+    Every change of a wallet goes through recordLedger, together with the
+    balance it left behind, so the history can be audited later:
+    - QueryLedger serves filtered, paginated pages (newest first).
+    - ReconcileLedger checks that the entries add up to the current balance.
+
+    The ledger is append-only; entries are never edited or removed.
+*/
+
+package game
+
+import "time"
+
+// Ledger entry types.
+const (
+	LedgerOpening       = "opening"        // Starting credits of a new wallet
+	LedgerDelivery      = "delivery"       // Contract payout on arrival
+	LedgerFuel          = "fuel"           // Fuel bought at a depot
+	LedgerModuleBuy     = "module_buy"     // Module bought (net of trade-ins)
+	LedgerModuleSell    = "module_sell"    // Module sold back to a shipyard
+	LedgerHullBuy       = "hull_buy"       // Ship bought for the fleet
+	LedgerFine          = "fine"           // Customs penalty
+	LedgerEscrowHold    = "escrow_hold"    // Collateral taken from the wallet on accept
+	LedgerEscrowRefund  = "escrow_refund"  // Collateral returned on delivery
+	LedgerEscrowForfeit = "escrow_forfeit" // Collateral lost on drop, expiry or loss in flight
+	LedgerLoan          = "loan"           // Credits borrowed at a bank
+	LedgerRepayment     = "repayment"      // Debt paid back at a bank
+	LedgerTow           = "tow"            // Emergency tow fee (the part paid from the wallet)
+	LedgerBankruptcy    = "bankruptcy"     // Wallet reset to StartingCredits
+	LedgerTransferIn    = "transfer_in"    // Credits wired by another player
+	LedgerTransferOut   = "transfer_out"   // Credits wired to another player
+	LedgerTradeIn       = "trade_in"       // Credits received in a trade
+	LedgerTradeOut      = "trade_out"      // Credits given in a trade
+	LedgerSubcontract   = "subcontract"    // Contractor's share of a subcontracted delivery
+	LedgerPostingHold   = "posting_hold"   // Payout of a posted contract taken into escrow
+	LedgerPostingRefund = "posting_refund" // Payout of a withdrawn or failed posting returned
+	LedgerPostingPaid   = "posting_paid"   // Payout of a delivered posting released to the hauler
+	LedgerCorpTax       = "corp_tax"       // Share of a delivery paid into the corporation treasury
+	LedgerCorpDeposit   = "corp_deposit"   // Credits paid into the treasury (including the founding fee)
+	LedgerCorpWithdraw  = "corp_withdraw"  // Credits taken out of the treasury
+	LedgerAchievement   = "achievement"    // Reward for unlocking an achievement
+)
+
+// Ledger query limits.
+const (
+	DefaultLedgerPage = 50
+	MaxLedgerPage     = 500
+)
+
+// recordLedger appends a movement to the player's ledger.
+// 'amount' is the change of the wallet, which must already be applied.
+func recordLedger(p *Player, kind string, amount int, ref, msg string) {
+	p.Ledger = append(p.Ledger, LedgerEntry{
+		Timestamp: time.Now().Unix(),
+		Type:      kind,
+		Amount:    amount,
+		Balance:   p.Credits,
+		Ref:       ref,
+		Message:   msg,
+	})
+}
+
+// Matches reports whether a ledger entry passes the filter.
+func (f LedgerFilter) Matches(e LedgerEntry) bool {
+	return (f.Type == "" || e.Type == f.Type) &&
+		(f.Ref == "" || e.Ref == f.Ref) &&
+		e.Timestamp >= f.From &&
+		(f.To == 0 || e.Timestamp <= f.To)
+}
+
+// QueryLedger returns one page of the player's ledger entries that match the filter, newest first.
+// A limit of 0 selects DefaultLedgerPage; larger limits are capped at MaxLedgerPage.
+// Note: Caller must hold DataLock
+func QueryLedger(p *Player, f LedgerFilter, offset, limit int) LedgerPage {
+	if limit <= 0 {
+		limit = DefaultLedgerPage
+	}
+	limit = min(limit, MaxLedgerPage)
+	offset = max(offset, 0)
+
+	page := LedgerPage{Entries: []LedgerEntry{}, Offset: offset, Limit: limit}
+	for i := len(p.Ledger) - 1; i >= 0; i-- {
+		e := p.Ledger[i]
+		if !f.Matches(e) {
+			continue
+		}
+		if page.Total >= offset && len(page.Entries) < limit {
+			page.Entries = append(page.Entries, e)
+		}
+		page.Total++
+	}
+	return page
+}
+
+// ReconcileLedger checks the ledger against the wallet: every entry's running balance
+// must follow from the previous one, and the movements must add up to the current balance.
+// Note: Caller must hold DataLock
+func ReconcileLedger(p *Player) LedgerReconciliation {
+	rec := LedgerReconciliation{Entries: len(p.Ledger), Balance: p.Credits, BrokenAt: -1}
+	for i, e := range p.Ledger {
+		rec.Sum += e.Amount
+		if rec.BrokenAt == -1 && e.Balance != rec.Sum {
+			rec.BrokenAt = i
+		}
+	}
+	rec.Drift = rec.Balance - rec.Sum
+	rec.OK = rec.Drift == 0 && rec.BrokenAt == -1
+	return rec
+}
@@ -11,6 +11,12 @@ package game
 
 import "math"
 
+// Reputation changes applied by the job board.
+const (
+	ReputationPerDelivery = 1 // Gained for every contract delivered
+	ReputationPerDrop     = 3 // Lost for every contract abandoned
+)
+
 // GetPlanet is a helper to retrieve a Planet pointer by its Key.
 // Returns nil if not found.
 func GetPlanet(key string) *Planet {
@@ -53,38 +59,83 @@ func CalculateDistance(p1, p2 []int) int64 {
 	return int64(math.Round(dist))
 }
 
+// DistanceToRoute returns the distance from point p to the straight route from a to b,
+// rounded like CalculateDistance.
+func DistanceToRoute(p, a, b []int) int64 {
+	if len(p) < 2 || len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+	dx, dy := float64(b[0]-a[0]), float64(b[1]-a[1])
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		// Project p onto the route, clamped to its end points
+		t = math.Max(0, math.Min(1, (float64(p[0]-a[0])*dx+float64(p[1]-a[1])*dy)/length))
+	}
+	x, y := float64(a[0])+t*dx, float64(a[1])+t*dy
+	return int64(math.Round(math.Hypot(float64(p[0])-x, float64(p[1])-y)))
+}
+
+// CalculateLoad counts the cargo units and passengers currently on board.
+func CalculateLoad(ship *Ship) (cargo, passengers int) {
+	for _, c := range ship.ActiveContracts {
+		if c.Type == "cargo" {
+			cargo += c.Quantity
+		} else {
+			passengers += c.Quantity
+		}
+	}
+	return cargo, passengers
+}
+
 // CalculateTotalMass computes the current weight of the ship.
-// Formula: BaseMass + (Cargo_Qty * Mass) + (Pax_Qty * Mass) + FuelMass
-func CalculateTotalMass() int64 {
-	total := PlayerShip.BaseMass
+// Formula: BaseMass + ModuleMass + (Cargo_Qty * Mass) + (Pax_Qty * Mass) + FuelMass
+func CalculateTotalMass(ship *Ship) int64 {
+	return CalculateMassWithFuel(ship, ship.Fuel)
+}
+
+// CalculateMassWithFuel computes the weight of the ship as if it carried 'fuel' units.
+// Used for "what-if" quotes (e.g., how heavy will I be after refueling?).
+func CalculateMassWithFuel(ship *Ship, fuel int64) int64 {
+	total := ship.Effective.BaseMass + CalculateModuleMass(ship)
 
 	// Sum mass of all active contracts
-	for _, c := range PlayerShip.ActiveContracts {
+	for _, c := range ship.ActiveContracts {
 		if c.Type == "cargo" {
 			total += int64(c.MassPerUnit * c.Quantity)
 		} else {
-			total += int64(CurrentUniverse.PassengerConfig.MassPerPassenger * c.Quantity)
+			total += int64(PassengerMass(c) * c.Quantity)
 		}
 	}
 
 	// Add mass of fuel (Fuel is treated as atomic units)
 	// 1 Unit of Fuel * FuelMassPerUnit = Total Fuel Mass
-	fuelMass := PlayerShip.Fuel * int64(CurrentUniverse.BalanceConfig.FuelMassPerUnit)
+	fuelMass := fuel * int64(CurrentUniverse.BalanceConfig.FuelMassPerUnit)
 
 	return total + fuelMass
 }
 
-// CalculateCurrentBurn determines the fuel cost per Light Year.
-// Formula: BaseBurn + ((CurrentMass - ReferenceMass) / Damping)
+// CalculateCurrentBurn determines the fuel cost per Light Year (before storms on the route).
+// Formula: (BaseBurn + ((CurrentMass - ReferenceMass) / Damping)) * HazardBurnMult
 // ReferenceMass = Ship Empty + 50% Fuel.
-func CalculateCurrentBurn() int64 {
-	currentMass := CalculateTotalMass()
+func CalculateCurrentBurn(ship *Ship) int64 {
+	return CalculateBurnWithFuel(ship, ship.Fuel)
+}
+
+// CalculateRouteBurn determines the fuel cost per Light Year of a flight to 'destKey'
+// as if the ship carried 'fuel' units. Storms along the route raise it (EventBurnMult).
+func CalculateRouteBurn(ship *Ship, fuel int64, destKey string) int64 {
+	return int64(float64(CalculateBurnWithFuel(ship, fuel)) * EventBurnMult(ship.LocationKey, destKey))
+}
+
+// CalculateBurnWithFuel determines the fuel cost per Light Year as if the ship carried 'fuel' units.
+func CalculateBurnWithFuel(ship *Ship, fuel int64) int64 {
+	currentMass := CalculateMassWithFuel(ship, fuel)
 
 	// 1. Calculate Reference Mass (The "Control" state)
 	// The ship is tuned to perform at BaseBurnRate when it has exactly 50% fuel and 0 cargo.
-	halfFuel := PlayerShip.MaxFuel / 2
+	halfFuel := ship.Effective.MaxFuel / 2
 	halfFuelMass := halfFuel * int64(CurrentUniverse.BalanceConfig.FuelMassPerUnit)
-	referenceMass := PlayerShip.BaseMass + halfFuelMass
+	referenceMass := ship.Effective.BaseMass + halfFuelMass
 
 	// 2. Determine Mass Delta
 	// Positive = Heavier than reference (Burn Penalty)
@@ -94,9 +145,12 @@ func CalculateCurrentBurn() int64 {
 	// 3. Apply Damping
 	// Damping represents the engine's ability to handle extra weight.
 	// A damping of 100 means: For every 100kg extra mass, burn 1 extra fuel.
-	burnAdjustment := massDiff / PlayerShip.BurnDamping
+	burnAdjustment := massDiff / ship.Effective.BurnDamping
+
+	finalBurn := ship.Effective.BaseBurnRate + burnAdjustment
 
-	finalBurn := PlayerShip.BaseBurnRate + burnAdjustment
+	// Cargo Traits: Hazardous cargo forces the engine into a safer, thirstier mode
+	finalBurn = int64(float64(finalBurn) * HazardBurnMult(ship))
 
 	// 4. Safety Clamp
 	// Prevent free travel or negative burn if the ship is extremely light.
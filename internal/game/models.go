@@ -14,10 +14,32 @@ package game
 // GameBalance stores global tuning variables loaded from 'universe.yaml'.
 // These values control the macro-economy and physics constants.
 type GameBalance struct {
-	StartingCredits    int `yaml:"starting_credits" json:"starting_credits"`         // Credits given to a new player/reset
-	FuelCostPerUnit    int `yaml:"fuel_cost_per_unit" json:"fuel_cost_per_unit"`     // Cost to buy 1.0 fuel at a depot
-	FuelMassPerUnit    int `yaml:"fuel_mass_per_unit" json:"fuel_mass_per_unit"`     // Weight of 1.0 fuel (Impacts burn rate)
-	DistancePayoutMult int `yaml:"distance_payout_mult" json:"distance_payout_mult"` // Credits earned per Light Year traveled
+	StartingCredits    int    `yaml:"starting_credits" json:"starting_credits"`         // Credits given to a new player/reset
+	StartingPlanet     string `yaml:"starting_planet" json:"starting_planet"`           // Planet Key where new players spawn (empty = first planet)
+	StartingHull       string `yaml:"starting_hull" json:"starting_hull"`               // Hull Key new players start with (empty = first hull)
+	FuelCostPerUnit    int    `yaml:"fuel_cost_per_unit" json:"fuel_cost_per_unit"`     // Cost to buy 1.0 fuel at a depot
+	FuelMassPerUnit    int    `yaml:"fuel_mass_per_unit" json:"fuel_mass_per_unit"`     // Weight of 1.0 fuel (Impacts burn rate)
+	DistancePayoutMult int    `yaml:"distance_payout_mult" json:"distance_payout_mult"` // Credits earned per Light Year traveled
+	MarketHistorySize  int    `yaml:"market_history_size" json:"market_history_size"`   // Number of economy ticks kept in the price history
+	MaxActiveEvents    int    `yaml:"max_active_events" json:"max_active_events"`       // Galactic events running at once (0 = DefaultMaxActiveEvents)
+
+	// Contract Shapes: Share of generated cargo jobs with several stops or follow-ups (see contracts.go).
+	MultiStopChance float64 `yaml:"multi_stop_chance" json:"multi_stop_chance"` // Chance a cargo job has several stops
+	MaxStops        int     `yaml:"max_stops" json:"max_stops"`                 // Most stops per job (0 = DefaultMaxStops)
+	ChainChance     float64 `yaml:"chain_chance" json:"chain_chance"`           // Chance a cargo job starts a chain
+	MaxChainLength  int     `yaml:"max_chain_length" json:"max_chain_length"`   // Most jobs per chain (0 = DefaultMaxChainLength)
+	ChainBonus      float64 `yaml:"chain_bonus" json:"chain_bonus"`             // Extra payout per completed step of a chain (0.1 = +10%)
+
+	// Escrow: Deposit demanded for valuable cargo contracts (see escrow.go).
+	CollateralRate      float64 `yaml:"collateral_rate" json:"collateral_rate"`           // Fraction of the goods value (BaseValue * Quantity) held (0 = no collateral)
+	CollateralThreshold int     `yaml:"collateral_threshold" json:"collateral_threshold"` // Goods value from which collateral applies
+	ContractTerm        int     `yaml:"contract_term" json:"contract_term"`               // Seconds to deliver a contract with collateral (0 = no deadline)
+
+	ModuleResaleRate float64 `yaml:"module_resale_rate" json:"module_resale_rate"` // Fraction of the price paid refunded when selling a module (0 = 0.5, max 1)
+
+	// Transfers: Limits on value sent to other players (see trade.go).
+	TransferMinEarnings int `yaml:"transfer_min_earnings" json:"transfer_min_earnings"` // Lifetime earnings required before sending anything
+	TransferDailyCap    int `yaml:"transfer_daily_cap" json:"transfer_daily_cap"`       // Most value sent per day (0 = no cap)
 }
 
 // ShipModule represents an installable upgrade for the player ship.
@@ -28,6 +50,43 @@ type ShipModule struct {
 	Cost         int    `yaml:"cost" json:"cost"`                   // Purchase price in Credits
 	StatModifier string `yaml:"stat_modifier" json:"stat_modifier"` // The struct field this mod affects (e.g., "cargo_capacity")
 	StatValue    int    `yaml:"stat_value" json:"stat_value"`       // The numeric amount added to the modifier
+	Mass         int    `yaml:"mass" json:"mass"`                   // Weight of the module itself while installed
+	PaidPrice    int    `yaml:"-" json:"paid_price,omitempty"`      // What the owner paid (set on purchase, basis of the resale value)
+
+	// Effects lists additional (or multiplicative) modifiers. StatModifier/StatValue is
+	// shorthand for a single additive effect and is applied alongside these.
+	Effects []ModuleEffect `yaml:"effects" json:"effects,omitempty"`
+
+	// Upgrade Tree: Prerequisites, tiers and stacking rules.
+	Requires       []string `yaml:"requires" json:"requires,omitempty"`               // Module Keys that must be installed first
+	MinReputation  int      `yaml:"min_reputation" json:"min_reputation,omitempty"`   // Reputation needed to buy
+	MinEarnings    int      `yaml:"min_earnings" json:"min_earnings,omitempty"`       // Lifetime delivery earnings needed to buy
+	Line           string   `yaml:"line" json:"line,omitempty"`                       // Upgrade line (e.g., "engine"); tiers replace each other
+	Tier           int      `yaml:"tier" json:"tier,omitempty"`                       // Position in the line; a higher tier replaces lower ones
+	MaxStack       int      `yaml:"max_stack" json:"max_stack,omitempty"`             // Max copies installed at once (0 = unlimited)
+	ExclusiveGroup string   `yaml:"exclusive_group" json:"exclusive_group,omitempty"` // Only one module of a group may be installed
+
+	// Faction Modules: Only sold inside the faction's territory, to captains in good standing.
+	Faction     string `yaml:"faction" json:"faction,omitempty"`
+	MinStanding int    `yaml:"min_standing" json:"min_standing,omitempty"` // Standing with Faction needed to buy
+}
+
+// ModuleOffer is a catalog entry as seen by the current player.
+// It explains whether the module can be bought right now and, if not, why.
+type ModuleOffer struct {
+	ShipModule
+	Available bool     `json:"available"`          // True if every purchase rule passes
+	Reasons   []string `json:"reasons,omitempty"`  // Human readable list of failed rules
+	Installed int      `json:"installed"`          // Copies currently installed
+	Replaces  []string `json:"replaces,omitempty"` // Lower-tier modules that will be traded in
+}
+
+// ModuleEffect modifies one ship stat.
+// Effective Stat = (Base + Sum(Add)) * Product(Mult)
+type ModuleEffect struct {
+	Stat string  `yaml:"stat" json:"stat"` // The stat key this effect targets (e.g., "cargo_capacity")
+	Add  int     `yaml:"add" json:"add"`   // Flat amount added to the base value
+	Mult float64 `yaml:"mult" json:"mult"` // Multiplier applied after all additions (0 = no multiplier)
 }
 
 // Contract represents a generated job (Cargo or Passenger) available on a planet.
@@ -41,6 +100,54 @@ type Contract struct {
 	OriginKey      string `json:"origin_key"`      // Planet Key where the contract starts
 	DestinationKey string `json:"destination_key"` // Planet Key where the contract must be delivered
 	Payout         int    `json:"payout"`          // Reward in Credits upon completion
+
+	// Passenger Details (empty for cargo)
+	Class           string `json:"class,omitempty"`            // PassengerClass Key (e.g., "pax_vip")
+	ComfortRequired int    `json:"comfort_required,omitempty"` // Minimum ship comfort rating to accept
+	BonusReputation int    `json:"bonus_reputation,omitempty"` // Extra reputation on delivery
+
+	// Cargo Traits (copied from the Commodity, see traits.go)
+	Traits    []string `json:"traits,omitempty"`    // e.g., "hazardous", "perishable"
+	Travelled int64    `json:"travelled,omitempty"` // LY flown since pickup (perishable goods decay)
+
+	// Itinerary: Multi-stop contracts drop part of the load at each stop, in order.
+	// DestinationKey is always the next stop; Quantity and Payout cover the stops still ahead.
+	Stops []ContractStop `json:"stops,omitempty"`
+
+	// Chains: Completing a chained contract unlocks a follow-up offer for the same player.
+	Chain     *ContractChain `json:"chain,omitempty"`
+	OfferedTo string         `json:"offered_to,omitempty"` // Player ID a follow-up is reserved for (empty = anyone)
+
+	// Escrow: Valuable cargo requires a deposit, held while the contract is aboard (see escrow.go).
+	Collateral int   `json:"collateral,omitempty"` // Credits deposited on accept, refunded on delivery
+	Deadline   int64 `json:"deadline,omitempty"`   // Unix time after which an accepted contract expires (0 = never)
+
+	// Subcontracting: The player who handed the job on keeps a share of the payout (see trade.go).
+	ContractorID    string `json:"contractor_id,omitempty"`
+	ContractorShare int    `json:"contractor_share,omitempty"` // Percent of the payout paid to the contractor
+
+	// Player Postings: Jobs put on a board by a player, paid from their escrow (see postings.go).
+	PostedBy string    `json:"posted_by,omitempty"` // Player ID of the poster (empty = generated by the planet)
+	Original *Contract `json:"-"`                   // The poster's own contract, completed when the posting is delivered
+
+	// Factions: The faction whose planet issued the job (see factions.go).
+	Faction     string `json:"faction,omitempty"`
+	MinStanding int    `json:"min_standing,omitempty"` // Standing with Faction required to accept (faction-only jobs)
+}
+
+// ContractStop is one drop-off of a multi-stop contract.
+type ContractStop struct {
+	PlanetKey string `json:"planet_key"` // Where the goods are dropped
+	Quantity  int    `json:"quantity"`   // Units delivered here
+	Payout    int    `json:"payout"`     // Credits paid for this stop
+	Distance  int64  `json:"distance"`   // LY from the previous stop (or the origin)
+}
+
+// ContractChain places a contract within a series of follow-up jobs.
+type ContractChain struct {
+	ID     string `json:"id"`     // Shared by every contract of the chain
+	Step   int    `json:"step"`   // 1-based position of this contract
+	Length int    `json:"length"` // Total number of contracts in the chain
 }
 
 // Planet represents a static location (Node) in the universe.
@@ -56,15 +163,53 @@ type Planet struct {
 	MaxCargo      int `json:"max_cargo" yaml:"max_cargo"`           // Maximum cargo contracts available
 	MinPassengers int `json:"min_passengers" yaml:"min_passengers"` // Minimum passenger contracts available
 	MaxPassengers int `json:"max_passengers" yaml:"max_passengers"` // Maximum passenger contracts available
+
+	// Services offered to docked ships: "shipyard", "fuel_depot", "passenger_terminal".
+	// All location-dependent actions (upgrades, refueling, passengers) check this list.
+	Services []string `json:"services" yaml:"services"`
+
+	// Refueling Configuration: Only used if the planet offers "fuel_depot" (defaults apply if omitted).
+	FuelDepot *FuelDepot `json:"fuel_depot,omitempty" yaml:"fuel_depot"`
+
+	// Shipyard Configuration: Only used if the planet offers "shipyard" (full catalog if omitted).
+	Shipyard *Shipyard `json:"shipyard,omitempty" yaml:"shipyard"`
+
+	// Territory: The faction controlling the planet (empty = independent, see factions.go).
+	Faction string `json:"faction,omitempty" yaml:"faction"`
 }
 
-// Ship represents the player's vessel, including its current state and configuration.
-type Ship struct {
-	Name        string `json:"name" yaml:"name"`         // Ship Name
-	LocationKey string `json:"location_key"`             // Current Planet Key where the ship is docked
-	Credits     int    `json:"credits"`                  // Current wallet balance
-	Fuel        int64  `json:"fuel"`                     // Current Fuel Level
-	MaxFuel     int64  `json:"max_fuel" yaml:"max_fuel"` // Fuel Tank Capacity
+// Shipyard configures the upgrade service of a planet.
+type Shipyard struct {
+	PriceMult float64         `json:"price_mult" yaml:"price_mult"` // Multiplier on catalog Cost (0 = 1.0)
+	Modules   []ModuleListing `json:"modules" yaml:"modules"`       // Modules sold here (empty = the full catalog)
+	Hulls     []string        `json:"hulls" yaml:"hulls"`           // Hull Keys sold here (empty = every hull)
+}
+
+// ModuleListing is one module sold by a shipyard, optionally at a fixed local price.
+type ModuleListing struct {
+	Key  string `json:"key" yaml:"key"`   // Module Key from 'ship_modules'
+	Cost int    `json:"cost" yaml:"cost"` // Local price in Credits (0 = catalog Cost * PriceMult)
+}
+
+// FuelDepot configures the refueling service of a planet.
+type FuelDepot struct {
+	PriceMult float64 `json:"price_mult" yaml:"price_mult"` // Multiplier on the global FuelCostPerUnit (0 = 1.0)
+	Capacity  int64   `json:"capacity" yaml:"capacity"`     // Max fuel units the depot can hold in stock
+	Restock   int64   `json:"restock" yaml:"restock"`       // Fuel units delivered to the depot per economy tick
+}
+
+// FuelMarket describes the live fuel situation at a planet (price and stock).
+type FuelMarket struct {
+	PlanetKey    string  `json:"planet_key"`
+	Available    bool    `json:"available"`      // False if the planet has no depot or the depot is empty
+	PricePerUnit float64 `json:"price_per_unit"` // Credits per 1.00 fuel after all modifiers
+	Stock        int64   `json:"stock"`          // Fuel units currently held by the depot
+	Capacity     int64   `json:"capacity"`       // Max fuel units the depot can hold
+}
+
+// ShipStats groups the numeric attributes of a ship that modules can modify.
+type ShipStats struct {
+	MaxFuel int64 `json:"max_fuel" yaml:"max_fuel"` // Fuel Tank Capacity
 
 	// Engine / Physics Stats
 	BaseBurnRate int64 `json:"base_burn_rate" yaml:"base_burn_rate"` // Fuel consumed per LY at Reference Mass
@@ -75,16 +220,298 @@ type Ship struct {
 	CargoCapacity  int `json:"cargo_capacity" yaml:"cargo_capacity"`     // Max units of cargo allowed
 	PassengerSlots int `json:"passenger_slots" yaml:"passenger_slots"`   // Max passengers allowed
 	MaxModuleSlots int `json:"max_module_slots" yaml:"max_module_slots"` // Max installed modules
+	Comfort        int `json:"comfort" yaml:"comfort"`                   // Cabin comfort rating (demanding passengers require more)
+}
+
+// HullClass is a purchasable ship chassis. Its stats become the Base stats of the ship.
+type HullClass struct {
+	Key         string    `json:"key" yaml:"key"`                 // Unique ID (e.g., "hull_hauler")
+	Name        string    `json:"name" yaml:"name"`               // Display name (default name of new ships)
+	Description string    `json:"description" yaml:"description"` // Flavor text
+	Cost        int       `json:"cost" yaml:"cost"`               // Purchase price in Credits
+	Stats       ShipStats `json:"stats" yaml:",inline"`           // Chassis stats before modules
+}
+
+// HullOffer is a hull sold at the local shipyard, with its local price.
+type HullOffer struct {
+	HullClass
+	Price     int  `json:"price"`      // Local price in Credits
+	CanAfford bool `json:"can_afford"` // Whether the player has enough Credits
+}
+
+// Ship represents a vessel owned by a player, including its current state and configuration.
+type Ship struct {
+	ID          string `json:"id"`               // Unique runtime ID (e.g., "SHP-1024-55")
+	Name        string `json:"name" yaml:"name"` // Ship Name
+	HullKey     string `json:"hull_key"`         // HullClass this ship was built from
+	LocationKey string `json:"location_key"`     // Current Planet Key where the ship is docked
+	Fuel        int64  `json:"fuel"`             // Current Fuel Level
+
+	// Stats: Base is the bare chassis (from the hull), Effective is Base + InstalledModules.
+	// Game logic must always read Effective; it is recomputed by RefreshShipStats.
+	Base      ShipStats `json:"base_stats"`
+	Effective ShipStats `json:"effective_stats"`
 
 	// Dynamic Lists
 	InstalledModules []ShipModule `json:"installed_modules"` // List of currently installed upgrades
+	StoredModules    []ShipModule `json:"stored_modules"`    // Uninstalled upgrades kept in storage (no slot, no effect)
 	ActiveContracts  []Contract   `json:"active_contracts"`  // List of jobs currently on board
+
+	// Automation: Secondary ships can fly a scripted route on the simulation loop.
+	Route       *RouteOrder     `json:"route,omitempty"` // Current orders (nil = idle, waits for its captain)
+	RouteReport RouteReport     `json:"route_report"`    // Lifetime profit of automated operation
+	RouteLog    []RouteLogEntry `json:"-"`               // Recent route events (served by /api/fleet/log)
+}
+
+// Player represents a captain. The wallet and career belong to the player,
+// while fuel, cargo and modules belong to each ship of their fleet.
+type Player struct {
+	ID      string `json:"id"`      // Unique ID chosen at registration (public, used to address the player)
+	Token   string `json:"-"`       // Secret session token issued at registration (see players.go)
+	Credits int    `json:"credits"` // Current wallet balance
+
+	// Career: Unlocks higher tiers of the upgrade tree.
+	Reputation       int `json:"reputation"`        // +1 per delivery, lost when dropping contracts
+	LifetimeEarnings int `json:"lifetime_earnings"` // Total Credits ever earned from deliveries
+
+	// Fleet: Every ship owned by the player. Exactly one is flown at a time.
+	ActiveShipID string  `json:"active_ship_id"`
+	Fleet        []*Ship `json:"fleet"`
+
+	// Accounting
+	Debt         int           `json:"debt"`         // Outstanding loans including interest (see bank.go)
+	Bankruptcies int           `json:"bankruptcies"` // Times the player was reset after going bankrupt
+	Escrow       int           `json:"escrow"`       // Collateral currently held for contracts aboard the fleet
+	Ledger       []LedgerEntry `json:"-"`            // Every recorded credit movement (see ledger.go)
+
+	// Transfers: Value sent to other players on the current day (see trade.go).
+	TransferredToday int   `json:"transferred_today"`
+	TransferDay      int64 `json:"-"` // Unix time / 86400
+
+	// Standing with each faction by key (see factions.go). Missing = neutral (0).
+	Standing map[string]int `json:"standing"`
+
+	CorpID string `json:"corp_id,omitempty"` // Corporation the player belongs to (see corporations.go)
+
+	// Statistics: All-time totals plus hourly buckets of the last week (see stats.go).
+	Stats        PlayerStats   `json:"stats"`
+	StatsHistory []StatsBucket `json:"-"`
+
+	// Achievements: Unlock time by achievement key, and the cosmetics they granted (see achievements.go).
+	Achievements map[string]int64 `json:"achievements"`
+	Cosmetics    []string         `json:"cosmetics"`
+}
+
+// PlayerStats accumulates what a player has done, in total or within a time window.
+type PlayerStats struct {
+	Distance        int64          `json:"distance"`          // LY flown
+	FuelBurned      int64          `json:"fuel_burned"`       // Atomic fuel units burned (see FuelUnitScale)
+	Trips           int            `json:"trips"`             // Flights completed
+	Accepted        int            `json:"accepted"`          // Contracts taken from job boards
+	Deliveries      int            `json:"deliveries"`        // Contracts (and stops) delivered
+	Earnings        int            `json:"earnings"`          // Credits earned on delivery (after subcontractor shares)
+	DeliveredByType map[string]int `json:"delivered_by_type"` // Deliveries by contract type ("cargo", "passenger")
+	UnitsDelivered  map[string]int `json:"units_delivered"`   // Units delivered by ItemKey
+	DeliveriesTo    map[string]int `json:"deliveries_to"`     // Deliveries by destination planet
+}
+
+// StatsBucket holds the statistics recorded during one hour.
+type StatsBucket struct {
+	Hour  int64       `json:"hour"` // Unix time / 3600
+	Stats PlayerStats `json:"stats"`
+}
+
+// NetWorth values everything a player owns.
+type NetWorth struct {
+	Credits int `json:"credits"`
+	Escrow  int `json:"escrow"`  // Collateral and posting payouts held in escrow
+	Ships   int `json:"ships"`   // Hulls at resale value
+	Modules int `json:"modules"` // Installed and stored modules at resale value
+	Debt    int `json:"debt"`
+	Total   int `json:"total"`
+}
+
+// StatsReport is a player's statistics in every window plus their net worth.
+type StatsReport struct {
+	Windows  map[string]PlayerStats `json:"windows"` // "all", "hour", "day", "week"
+	NetWorth NetWorth               `json:"net_worth"`
+}
+
+// LeaderboardEntry is one ranked player.
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	PlayerID string `json:"player_id"`
+	CorpID   string `json:"corp_id,omitempty"`
+	Value    int64  `json:"value"`
+}
+
+// LedgerEntry records one credit movement of a player.
+type LedgerEntry struct {
+	Timestamp int64  `json:"timestamp"`     // Unix time (seconds)
+	Type      string `json:"type"`          // e.g., "escrow_hold", "escrow_refund"
+	Amount    int    `json:"amount"`        // Credit change of the wallet (+ in, - out)
+	Balance   int    `json:"balance"`       // Wallet balance after the movement
+	Ref       string `json:"ref,omitempty"` // Contract ID, module/hull key or planet key the movement relates to
+	Message   string `json:"message"`
+}
+
+// TradeOffer is a pending exchange between two players. Nothing moves until the
+// recipient accepts; then every item changes hands at once or not at all.
+type TradeOffer struct {
+	ID        string `json:"id"`
+	FromID    string `json:"from_id"`    // Player making the offer
+	ToID      string `json:"to_id"`      // Player who may accept
+	CreatedAt int64  `json:"created_at"` // Unix time (seconds)
+
+	// Given by the offering player
+	Credits   int      `json:"credits,omitempty"`
+	Contracts []string `json:"contracts,omitempty"` // IDs of contracts aboard the active ship (subcontracted)
+	Modules   []string `json:"modules,omitempty"`   // Keys of modules in the active ship's storage
+	Share     int      `json:"share,omitempty"`     // Percent of each contract's payout the offering player keeps
+
+	// Asked in return
+	RequestCredits int `json:"request_credits,omitempty"`
+}
+
+// LedgerFilter selects ledger entries. Empty fields match everything.
+type LedgerFilter struct {
+	Type string // Entry type (e.g., "delivery")
+	Ref  string // Exact reference
+	From int64  // Unix seconds, inclusive
+	To   int64  // Unix seconds, inclusive (0 = now)
+}
+
+// LedgerPage is one page of a ledger query, newest entries first.
+type LedgerPage struct {
+	Entries []LedgerEntry `json:"entries"`
+	Total   int           `json:"total"` // Entries matching the filter
+	Offset  int           `json:"offset"`
+	Limit   int           `json:"limit"`
+}
+
+// LedgerReconciliation compares the ledger with the wallet.
+type LedgerReconciliation struct {
+	Entries  int  `json:"entries"`
+	Sum      int  `json:"sum"`       // Sum of all recorded movements
+	Balance  int  `json:"balance"`   // Current wallet balance
+	Drift    int  `json:"drift"`     // Balance - Sum (0 when every change was recorded)
+	BrokenAt int  `json:"broken_at"` // Index of the first entry whose running balance doesn't follow (-1 = none)
+	OK       bool `json:"ok"`
+}
+
+// RefuelQuote describes the outcome of a (potential) refuel at the current location.
+type RefuelQuote struct {
+	Amount       int64   `json:"amount"`         // Fuel units that will be pumped
+	Cost         int     `json:"cost"`           // Exact price in Credits (fractions of a credit round up)
+	PricePerUnit float64 `json:"price_per_unit"` // Local price per 1.00 fuel
+	FuelAfter    int64   `json:"fuel_after"`     // Tank level after refueling
+	BurnAfter    int64   `json:"burn_after"`     // Burn rate per LY at the new (heavier) mass, before storms
+	CanAfford    bool    `json:"can_afford"`     // Whether the player has enough Credits
+}
+
+// TravelResult summarizes a completed flight.
+type TravelResult struct {
+	Distance  int64      `json:"distance"`             // LY flown
+	FuelUsed  int64      `json:"fuel_used"`            // Fuel units burned
+	Delivered []Contract `json:"delivered"`            // Contracts completed (or stops of multi-stop contracts) on arrival
+	Payout    int        `json:"payout"`               // Credits earned on arrival
+	Refunded  int        `json:"refunded,omitempty"`   // Collateral returned for delivered cargo
+	FollowUps []Contract `json:"follow_ups,omitempty"` // Chain offers unlocked on arrival, waiting on the local board
+	CorpTax   int        `json:"corp_tax,omitempty"`   // Share of the payout paid into the corporation treasury
+
+	Lost        []Contract `json:"lost,omitempty"`        // Fragile cargo destroyed in flight
+	Confiscated []Contract `json:"confiscated,omitempty"` // Contraband seized by customs
+	Fine        int        `json:"fine,omitempty"`        // Credits paid to customs
+}
+
+// RouteOrder is a scripted sequence of steps a secondary ship executes on its own.
+// One step runs per simulation tick; with Repeat the route loops forever.
+type RouteOrder struct {
+	Steps  []RouteStep `json:"steps"`
+	Repeat bool        `json:"repeat"`
+	Cursor int         `json:"cursor"`           // Index of the next step to run
+	Status string      `json:"status"`           // "running", "stalled" or "finished"
+	Reason string      `json:"reason,omitempty"` // Why the route stalled
+}
+
+// RouteStep is a single instruction of a route.
+type RouteStep struct {
+	Action    string         `json:"action"`               // "accept", "travel" or "refuel"
+	PlanetKey string         `json:"planet_key,omitempty"` // travel: Destination
+	Filter    ContractFilter `json:"filter"`               // accept: Which contracts to take
+	Budget    int            `json:"budget,omitempty"`     // refuel: Max Credits to spend (0 = full tank)
+}
+
+// ContractFilter selects contracts from a job board. Empty fields match anything.
+type ContractFilter struct {
+	Type           string `json:"type,omitempty"`            // "cargo" or "passenger"
+	ItemKey        string `json:"item_key,omitempty"`        // Commodity (or passenger) key
+	DestinationKey string `json:"destination_key,omitempty"` // Where the contract goes
+	MinPayout      int    `json:"min_payout,omitempty"`      // Ignore cheaper jobs
+	MaxContracts   int    `json:"max_contracts,omitempty"`   // Take at most this many per visit (0 = as many as fit)
+}
+
+// RouteLogEntry records one event of an automated ship.
+type RouteLogEntry struct {
+	Timestamp   int64  `json:"timestamp"`    // Unix time (seconds)
+	Action      string `json:"action"`       // Step action that produced the entry
+	LocationKey string `json:"location_key"` // Where the ship was afterwards
+	Message     string `json:"message"`
+	Credits     int    `json:"credits"` // Credit change caused by the step (payouts +, fuel -)
+}
+
+// RouteReport accumulates the economics of a ship's automated operation.
+type RouteReport struct {
+	Revenue    int   `json:"revenue"`     // Payouts earned
+	FuelCost   int   `json:"fuel_cost"`   // Credits spent on fuel
+	Fines      int   `json:"fines"`       // Credits paid to customs
+	CorpTax    int   `json:"corp_tax"`    // Credits paid into the corporation treasury
+	Losses     int   `json:"losses"`      // Contracts destroyed or seized in flight
+	Profit     int   `json:"profit"`      // Revenue - FuelCost - Fines - CorpTax
+	Deliveries int   `json:"deliveries"`  // Contracts completed
+	Distance   int64 `json:"distance"`    // LY flown
+	FuelBurned int64 `json:"fuel_burned"` // Fuel units burned
+	Loops      int   `json:"loops"`       // Completed passes through a repeating route
+}
+
+// ShipReport is one line of a FleetReport.
+type ShipReport struct {
+	ShipID      string      `json:"ship_id"`
+	Name        string      `json:"name"`
+	LocationKey string      `json:"location_key"`
+	Active      bool        `json:"active"` // Flown by the player (never automated)
+	Route       *RouteOrder `json:"route,omitempty"`
+	Report      RouteReport `json:"report"`
+}
+
+// FleetReport lists the automated earnings of every ship of a player, plus the fleet total.
+type FleetReport struct {
+	Ships []ShipReport `json:"ships"`
+	Total RouteReport  `json:"total"`
 }
 
 // PassengerConfig defines the baseline variables for generating passenger jobs.
 type PassengerConfig struct {
-	BaseTicketPrice  int `yaml:"base_ticket_price"`  // Flat fee added to distance calculation
-	MassPerPassenger int `yaml:"mass_per_passenger"` // Standard weight of a passenger + luggage
+	BaseTicketPrice    int              `yaml:"base_ticket_price"`   // Flat fee added to distance calculation
+	MassPerPassenger   int              `yaml:"mass_per_passenger"`  // Standard weight of a passenger + luggage
+	ComfortRequirement int              `yaml:"comfort_requirement"` // Minimum comfort rating for every passenger class
+	Classes            []PassengerClass `yaml:"classes"`             // Passenger varieties (empty = a single economy class)
+}
+
+// PassengerClass defines one variety of passenger contract and its payout formula.
+// Payout = (BaseFare + PerLY * Distance) * Quantity
+type PassengerClass struct {
+	Key                string   `yaml:"key" json:"key"`                                 // Unique ID (e.g., "pax_vip")
+	Name               string   `yaml:"name" json:"name"`                               // Display name used as the contract's ItemName
+	Weight             int      `yaml:"weight" json:"weight"`                           // Relative generation frequency
+	MinQuantity        int      `yaml:"min_quantity" json:"min_quantity"`               // Smallest party size (0 = 1)
+	MaxQuantity        int      `yaml:"max_quantity" json:"max_quantity"`               // Largest party size (0 = MinQuantity)
+	BaseFare           int      `yaml:"base_fare" json:"base_fare"`                     // Flat fare per passenger
+	PerLY              int      `yaml:"per_ly" json:"per_ly"`                           // Fare per passenger per LY
+	Mass               int      `yaml:"mass" json:"mass"`                               // Weight per passenger (0 = mass_per_passenger)
+	ComfortRequirement int      `yaml:"comfort_requirement" json:"comfort_requirement"` // Minimum ship comfort rating
+	BonusReputation    int      `yaml:"bonus_reputation" json:"bonus_reputation"`       // Extra reputation on delivery
+	Origins            []string `yaml:"origins" json:"origins,omitempty"`               // Planets where they board (empty = any terminal)
 }
 
 // Commodity represents a tradeable good.
@@ -93,16 +520,275 @@ type Commodity struct {
 	Name      string `yaml:"name" json:"name"`             // Display Name
 	BaseValue int    `yaml:"base_value" json:"base_value"` // Baseline price before market multipliers
 	Mass      int    `yaml:"mass" json:"mass"`             // Weight per unit
+
+	Traits        []string `yaml:"traits" json:"traits,omitempty"`                 // "hazardous", "perishable", "fragile", "contraband"
+	PickupPlanets []string `yaml:"pickup_planets" json:"pickup_planets,omitempty"` // Contraband: The only planets it can be loaded at
+}
+
+// CargoTraits tunes the gameplay rules of commodity traits (see traits.go).
+type CargoTraits struct {
+	Hazardous  HazardousTrait  `yaml:"hazardous" json:"hazardous"`
+	Perishable PerishableTrait `yaml:"perishable" json:"perishable"`
+	Fragile    FragileTrait    `yaml:"fragile" json:"fragile"`
+	Contraband ContrabandTrait `yaml:"contraband" json:"contraband"`
+}
+
+// HazardousTrait: Needs a containment module and makes the engine work harder.
+type HazardousTrait struct {
+	ContainmentModule string  `yaml:"containment_module" json:"containment_module"` // Module Key required to accept
+	BurnMult          float64 `yaml:"burn_mult" json:"burn_mult"`                   // Burn multiplier while any is aboard (0 = 1.0)
+}
+
+// PerishableTrait: Payout shrinks with every LY flown.
+type PerishableTrait struct {
+	DecayPerLY float64 `yaml:"decay_per_ly" json:"decay_per_ly"` // Fraction of the payout lost per LY
+	MinValue   float64 `yaml:"min_value" json:"min_value"`       // Floor as a fraction of the payout
+}
+
+// FragileTrait: Each flight may destroy the shipment.
+type FragileTrait struct {
+	LossChance float64 `yaml:"loss_chance" json:"loss_chance"` // Chance per flight the contract is lost
+}
+
+// ContrabandTrait: Customs may seize it on arrival and fine the captain.
+type ContrabandTrait struct {
+	InspectionChance float64 `yaml:"inspection_chance" json:"inspection_chance"` // Chance per arrival at a "customs" planet
+	FineMult         float64 `yaml:"fine_mult" json:"fine_mult"`                 // Fine = Payout * FineMult
 }
 
 // Universe is the root configuration struct, mapping to the entire 'universe.yaml' file.
 type Universe struct {
-	BalanceConfig    GameBalance     `yaml:"game_balance"`
-	PlayerShipConfig Ship            `yaml:"player_ship"`
-	Commodities      []Commodity     `yaml:"commodities"`
-	Planets          []Planet        `yaml:"planets"`
-	ShipModules      []ShipModule    `yaml:"ship_modules"`
-	PassengerConfig  PassengerConfig `yaml:"passenger_config"`
+	BalanceConfig   GameBalance             `yaml:"game_balance"`
+	Hulls           []HullClass             `yaml:"hulls"`
+	Commodities     []Commodity             `yaml:"commodities"`
+	CargoTraits     CargoTraits             `yaml:"cargo_traits"`
+	Planets         []Planet                `yaml:"planets"`
+	ShipModules     []ShipModule            `yaml:"ship_modules"`
+	PassengerConfig PassengerConfig         `yaml:"passenger_config"`
+	NPCTraders      []NPCConfig             `yaml:"npc_traders"`
+	Events          []EventDefinition       `yaml:"galactic_events"`
+	Banking         BankConfig              `yaml:"banking"`
+	FactionConfig   FactionConfig           `yaml:"faction_config"`
+	Corporations    CorpConfig              `yaml:"corporations"`
+	Achievements    []AchievementDefinition `yaml:"achievements"`
+}
+
+// AchievementDefinition is a milestone and its reward. Every condition that is set must hold at once.
+type AchievementDefinition struct {
+	Key         string `yaml:"key" json:"key"`                 // Unique ID (e.g., "ach_grand_tour")
+	Name        string `yaml:"name" json:"name"`               // Display name
+	Description string `yaml:"description" json:"description"` // Shown to the player
+	Event       string `yaml:"event" json:"event,omitempty"`   // "travel", "accept", "buy_module" (empty = any)
+
+	// Conditions
+	AtPlanet            string           `yaml:"at_planet" json:"at_planet,omitempty"`                       // Active ship docked at this planet
+	Carrying            string           `yaml:"carrying" json:"carrying,omitempty"`                         // ItemKey aboard the active ship
+	FullHold            bool             `yaml:"full_hold" json:"full_hold,omitempty"`                       // Active ship's cargo bay filled to capacity
+	ModuleInstalled     string           `yaml:"module_installed" json:"module_installed,omitempty"`         // Module Key installed on the active ship
+	DeliveredEverywhere bool             `yaml:"delivered_everywhere" json:"delivered_everywhere,omitempty"` // At least one delivery to every planet
+	UnitsDelivered      map[string]int   `yaml:"units_delivered" json:"units_delivered,omitempty"`           // Minimum units delivered by ItemKey (all time)
+	Stats               map[string]int64 `yaml:"stats" json:"stats,omitempty"`                               // Minimum all-time value by leaderboard metric
+
+	// Rewards
+	RewardCredits  int    `yaml:"reward_credits" json:"reward_credits,omitempty"`
+	RewardCosmetic string `yaml:"reward_cosmetic" json:"reward_cosmetic,omitempty"` // Title, paint or decal key for the client
+}
+
+// AchievementUnlock is pushed to the player when an achievement is unlocked.
+type AchievementUnlock struct {
+	AchievementDefinition
+	UnlockedAt int64 `json:"unlocked_at"`
+}
+
+// AchievementStatus is an achievement as seen by one player.
+type AchievementStatus struct {
+	AchievementDefinition
+	Unlocked   bool  `json:"unlocked"`
+	UnlockedAt int64 `json:"unlocked_at,omitempty"`
+}
+
+// FactionConfig defines the factions and how standing with them changes (see factions.go).
+type FactionConfig struct {
+	StandingPerDelivery int       `yaml:"standing_per_delivery"` // Gained with the issuing faction per delivered contract
+	StandingPerDrop     int       `yaml:"standing_per_drop"`     // Lost with the issuing faction per abandoned contract
+	RivalPenalty        int       `yaml:"rival_penalty"`         // Lost with each rival of the issuing faction per delivery
+	MaxStanding         int       `yaml:"max_standing"`          // Standing is clamped to +/- this value (0 = unlimited)
+	PricePerStanding    float64   `yaml:"price_per_standing"`    // Payout bonus and fuel discount per standing point (0.002 = 0.2%)
+	Factions            []Faction `yaml:"factions"`
+}
+
+// Faction is a power controlling a group of planets.
+type Faction struct {
+	Key               string   `yaml:"key" json:"key"`                               // Unique ID (e.g., "fac_compact")
+	Name              string   `yaml:"name" json:"name"`                             // Display name
+	Description       string   `yaml:"description" json:"description"`               // Flavor text
+	Rivals            []string `yaml:"rivals" json:"rivals,omitempty"`               // Faction Keys that resent deliveries for this faction
+	ExclusiveChance   float64  `yaml:"exclusive_chance" json:"exclusive_chance"`     // Share of cargo jobs in the territory reserved for trusted captains
+	ExclusiveStanding int      `yaml:"exclusive_standing" json:"exclusive_standing"` // Standing required for reserved jobs
+	ExclusiveBonus    float64  `yaml:"exclusive_bonus" json:"exclusive_bonus"`       // Payout bonus of reserved jobs (0.25 = +25%)
+}
+
+// FactionStatus is a faction as seen by one player.
+type FactionStatus struct {
+	Faction
+	Territory  []string `json:"territory"`   // Planet Keys controlled by the faction
+	Standing   int      `json:"standing"`    // The player's standing
+	PayoutMult float64  `json:"payout_mult"` // Applied to the faction's contract payouts
+	FuelMult   float64  `json:"fuel_mult"`   // Applied to fuel prices in the territory
+	Exclusive  bool     `json:"exclusive"`   // True if the player may take faction-only jobs
+}
+
+// CorpConfig tunes player corporations (see corporations.go).
+type CorpConfig struct {
+	FoundingFee    int `yaml:"founding_fee" json:"founding_fee"`         // Paid into the treasury of a new corporation
+	MaxMembers     int `yaml:"max_members" json:"max_members"`           // Member limit per corporation (0 = unlimited)
+	DefaultTaxRate int `yaml:"default_tax_rate" json:"default_tax_rate"` // Percent of member payouts paid to the treasury
+	MaxTaxRate     int `yaml:"max_tax_rate" json:"max_tax_rate"`         // Highest tax rate a founder may set
+	ChatHistory    int `yaml:"chat_history" json:"chat_history"`         // Corp chat messages kept (0 = 50)
+}
+
+// Corporation is a group of players sharing a treasury.
+type Corporation struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	TaxRate   int               `json:"tax_rate"` // Percent of every member delivery paid into the treasury
+	Treasury  int               `json:"treasury"`
+	CreatedAt int64             `json:"created_at"`
+	Members   []CorpMember      `json:"members"` // In joining order
+	Invites   []string          `json:"invites"` // Player IDs invited by officers, not yet joined
+	Stats     CorpStats         `json:"stats"`
+	Chat      []CorpChatMessage `json:"chat"` // Most recent messages, oldest first
+}
+
+// CorpMember is one player in a corporation.
+type CorpMember struct {
+	PlayerID string `json:"player_id"`
+	Role     string `json:"role"` // "founder", "officer" or "member"
+	JoinedAt int64  `json:"joined_at"`
+}
+
+// CorpStats accumulates the deliveries of all members since the corporation was founded.
+type CorpStats struct {
+	Deliveries   int   `json:"deliveries"`    // Contracts delivered by members
+	Payouts      int   `json:"payouts"`       // Credits earned by members (before tax)
+	TaxCollected int   `json:"tax_collected"` // Credits paid into the treasury as tax
+	Distance     int64 `json:"distance"`      // LY flown on delivery trips
+}
+
+// CorpChatMessage is one line of corporation chat.
+type CorpChatMessage struct {
+	Timestamp int64  `json:"timestamp"`
+	CorpID    string `json:"corp_id"`
+	PlayerID  string `json:"player_id"`
+	Text      string `json:"text"`
+}
+
+// CorpStatus is a corporation plus the current totals of its members.
+type CorpStatus struct {
+	*Corporation
+	Combined CorpTotals `json:"combined"`
+}
+
+// CorpTotals sums the wallets and fleets of the current members.
+type CorpTotals struct {
+	Credits    int `json:"credits"`
+	Reputation int `json:"reputation"`
+	Ships      int `json:"ships"`
+}
+
+// CorpSummary is a corporation as listed to everyone.
+type CorpSummary struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Members  int    `json:"members"`
+	TaxRate  int    `json:"tax_rate"`
+	Treasury int    `json:"treasury"`
+	Invited  bool   `json:"invited"` // The requesting player may join
+}
+
+// BankConfig tunes loans, interest and emergency tows (see bank.go).
+type BankConfig struct {
+	MaxLoan           int     `yaml:"max_loan" json:"max_loan"`                       // Debt limit of a player without reputation
+	LoanPerReputation int     `yaml:"loan_per_reputation" json:"loan_per_reputation"` // Extra debt limit per reputation point
+	InterestRate      float64 `yaml:"interest_rate" json:"interest_rate"`             // Interest added to the debt per economy tick (0.001 = 0.1%)
+	TowBaseFee        int     `yaml:"tow_base_fee" json:"tow_base_fee"`               // Flat fee of an emergency tow
+	TowPerLY          int     `yaml:"tow_per_ly" json:"tow_per_ly"`                   // Tow fee per LY towed
+}
+
+// TowResult describes an emergency tow (or a quote for one).
+type TowResult struct {
+	FromKey  string `json:"from_key"`
+	ToKey    string `json:"to_key"`   // Nearest depot, preferring one with a bank
+	Distance int64  `json:"distance"` // LY towed
+	Cost     int    `json:"cost"`     // Total fee
+	Paid     int    `json:"paid"`     // Part of the fee paid from the wallet
+	Financed int    `json:"financed"` // Part of the fee added to the debt
+}
+
+// BankStatus summarizes a player's debt and the banking options at the active ship's location.
+type BankStatus struct {
+	BankHere      bool       `json:"bank_here"`       // Loans and repayments are possible here
+	Debt          int        `json:"debt"`            // Outstanding debt including interest
+	LoanLimit     int        `json:"loan_limit"`      // Maximum debt the banks allow
+	InterestRate  float64    `json:"interest_rate"`   // Per economy tick
+	Stranded      bool       `json:"stranded"`        // The active ship can't leave on its own
+	Tow           *TowResult `json:"tow,omitempty"`   // Quote for an emergency tow (only when stranded)
+	CanGoBankrupt bool       `json:"can_go_bankrupt"` // Stranded with a negative net worth
+	Bankruptcies  int        `json:"bankruptcies"`
+}
+
+// EventDefinition describes a random galactic event that may strike during an economy tick.
+type EventDefinition struct {
+	Key         string        `yaml:"key" json:"key"`                   // Unique ID (e.g., "evt_famine")
+	Name        string        `yaml:"name" json:"name"`                 // Display name
+	Description string        `yaml:"description" json:"description"`   // Flavor text
+	Chance      float64       `yaml:"chance" json:"chance"`             // Probability of starting on each tick (0.0 - 1.0)
+	MinDuration int           `yaml:"min_duration" json:"min_duration"` // Shortest duration in economy ticks
+	MaxDuration int           `yaml:"max_duration" json:"max_duration"` // Longest duration in economy ticks
+	Planets     []string      `yaml:"planets" json:"planets"`           // Planets the event can strike (empty = any)
+	Effects     []EventEffect `yaml:"effects" json:"effects"`
+}
+
+// EventEffect is one modifier applied while an event is active, centered on the struck planet.
+type EventEffect struct {
+	Type   string  `yaml:"type" json:"type"`               // "demand", "halt_production" or "burn"
+	Item   string  `yaml:"item" json:"item,omitempty"`     // Commodity Key ("passenger" for tickets, empty = everything)
+	Mult   float64 `yaml:"mult" json:"mult,omitempty"`     // Payout (demand) or burn multiplier
+	Radius int64   `yaml:"radius" json:"radius,omitempty"` // burn: LY around the planet affected (0 = flights from or to it only)
+}
+
+// ActiveEvent is a running instance of an EventDefinition.
+type ActiveEvent struct {
+	ID             string        `json:"id"`  // Unique runtime ID (e.g., "EVT-1024-55")
+	Key            string        `json:"key"` // EventDefinition Key
+	Name           string        `json:"name"`
+	Description    string        `json:"description"`
+	PlanetKey      string        `json:"planet_key"` // Where the event struck
+	Effects        []EventEffect `json:"effects"`
+	StartedAt      int64         `json:"started_at"`      // Unix time (seconds)
+	Duration       int           `json:"duration"`        // Total length in economy ticks
+	RemainingTicks int           `json:"remaining_ticks"` // Ticks left before the event ends
+}
+
+// NPCConfig defines a group of simulated traders that compete with players for contracts.
+type NPCConfig struct {
+	Name     string `yaml:"name" json:"name"`         // Display name prefix (e.g., "Guild Hauler")
+	Count    int    `yaml:"count" json:"count"`       // Number of ships in the group
+	Strategy string `yaml:"strategy" json:"strategy"` // "greedy", "nearest" or "random"
+	Hull     string `yaml:"hull" json:"hull"`         // Hull Key the ships are built from (empty = starting hull)
+}
+
+// NPCTrader is a simulated hauler. It flies a regular Ship with its own wallet,
+// so it follows the same capacity, fuel and market rules as a player.
+type NPCTrader struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Strategy   string  `json:"strategy"`
+	Wallet     *Player `json:"-"` // Private wallet (never registered in Players)
+	Ship       *Ship   `json:"ship"`
+	Deliveries int     `json:"deliveries"` // Contracts completed
+	Earnings   int     `json:"earnings"`   // Credits earned from payouts
+	Tows       int     `json:"tows"`       // Times the trader was stranded and towed back to fuel
 }
 
 // MarketState tracks the dynamic "Heat" (Supply/Demand pressure) of the economy.
@@ -115,4 +801,38 @@ type MarketState struct {
 	// DestHeat maps PlanetKey -> CommodityKey -> Saturation Multiplier.
 	// > 1.0 means the market is flooded (low prices).
 	DestHeat map[string]map[string]float64
+
+	// FuelStock maps PlanetKey -> Fuel units currently held by the planet's depot.
+	FuelStock map[string]int64
+}
+
+// MarketSample is a single point in the price history of one Planet/Commodity pair.
+// One sample is recorded per economy tick.
+type MarketSample struct {
+	Timestamp  int64   `json:"timestamp"`   // Unix time (seconds) when the tick was recorded
+	SourceHeat float64 `json:"source_heat"` // Scarcity multiplier at the time of the tick
+	DestHeat   float64 `json:"dest_heat"`   // Saturation multiplier at the time of the tick
+	Price      int     `json:"price"`       // Derived delivery value per unit (BaseValue / DestHeat)
+}
+
+// MarketAggregate summarises every MarketSample that fell inside one time bucket.
+// Used for downsampled charts (OHLC style for price, averages for heat).
+type MarketAggregate struct {
+	Start         int64   `json:"start"`           // Unix time (seconds) where the bucket begins
+	End           int64   `json:"end"`             // Unix time (seconds) where the bucket ends (exclusive)
+	Samples       int     `json:"samples"`         // Number of ticks that fell inside the bucket
+	AvgSourceHeat float64 `json:"avg_source_heat"` // Mean scarcity over the bucket
+	AvgDestHeat   float64 `json:"avg_dest_heat"`   // Mean saturation over the bucket
+	OpenPrice     int     `json:"open_price"`      // Price at the first tick of the bucket
+	ClosePrice    int     `json:"close_price"`     // Price at the last tick of the bucket
+	MinPrice      int     `json:"min_price"`       // Lowest price seen in the bucket
+	MaxPrice      int     `json:"max_price"`       // Highest price seen in the bucket
+	AvgPrice      float64 `json:"avg_price"`       // Mean price over the bucket
+}
+
+// MarketSnapshot captures the full heat map of the universe at a single tick.
+// Keyed as PlanetKey -> CommodityKey -> Sample.
+type MarketSnapshot struct {
+	Timestamp int64
+	Points    map[string]map[string]MarketSample
 }
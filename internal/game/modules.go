@@ -0,0 +1,532 @@
+/*
+Package game
+File: modules.go
+Description:
+    Handles the lifecycle of ship modules (Upgrades).
+    This includes:
+    1. Deriving the Effective ship stats from the chassis plus installed modules.
+    2. Enforcing the upgrade tree (prerequisites, tiers, stacking, exclusive groups).
+    3. Buying, uninstalling into storage, re-installing and selling modules.
+
+    Modules never mutate the base chassis stats. Effective stats are always
+    recomputed from scratch, so a hot reload of module values applies cleanly.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Module validation errors.
+var (
+	ErrModuleNotInstalled = errors.New("module is not installed")
+	ErrModuleNotStored    = errors.New("module is not in storage")
+	ErrNoModuleSlots      = errors.New("no module slots available")
+	ErrCapacityInUse      = errors.New("removing module would leave active contracts over capacity")
+	ErrTankInUse          = errors.New("removing module would leave more fuel than the tank holds")
+	ErrSlotsInUse         = errors.New("removing module would leave more modules than slots")
+	ErrComfortInUse       = errors.New("removing module would leave passengers below their comfort requirement")
+	ErrContainmentInUse   = errors.New("removing module would leave hazardous cargo without containment")
+
+	ErrModuleNotFound      = errors.New("module not found")
+	ErrInsufficientCredits = errors.New("insufficient credits")
+	ErrModuleMaxStack      = errors.New("maximum number of copies already installed")
+	ErrModuleExclusive     = errors.New("conflicts with an installed module of the same group")
+	ErrModuleObsolete      = errors.New("same or higher tier of this line already installed")
+	ErrModulePrerequisite  = errors.New("required modules are not installed")
+	ErrReputationTooLow    = errors.New("reputation too low")
+	ErrEarningsTooLow      = errors.New("lifetime earnings too low")
+	ErrModuleRequired      = errors.New("another installed module requires this module")
+)
+
+// statFields maps every moddable stat key (as used in 'universe.yaml') to its field in ShipStats.
+func statFields(s *ShipStats) map[string]any {
+	return map[string]any{
+		"max_fuel":         &s.MaxFuel,
+		"base_burn_rate":   &s.BaseBurnRate,
+		"burn_damping":     &s.BurnDamping,
+		"base_mass":        &s.BaseMass,
+		"cargo_capacity":   &s.CargoCapacity,
+		"passenger_slots":  &s.PassengerSlots,
+		"max_module_slots": &s.MaxModuleSlots,
+		"comfort":          &s.Comfort,
+	}
+}
+
+// IsShipStat reports whether 'key' names a stat that modules can modify.
+func IsShipStat(key string) bool {
+	_, ok := statFields(&ShipStats{})[key]
+	return ok
+}
+
+// ValidateModules rejects module definitions that reference unknown stats or nonsensical values.
+// Called by LoadConfig so a typo in 'universe.yaml' fails loudly instead of charging for nothing.
+func ValidateModules(u *Universe) error {
+	// A rate above 1 would refund more than the purchase price
+	if rate := u.BalanceConfig.ModuleResaleRate; rate < 0 || rate > 1 {
+		return fmt.Errorf("module_resale_rate must be between 0 and 1, got %v", rate)
+	}
+
+	seen := make(map[string]bool)
+	for _, mod := range u.ShipModules {
+		if mod.Key == "" {
+			return fmt.Errorf("module %q: missing key", mod.Name)
+		}
+		if seen[mod.Key] {
+			return fmt.Errorf("module %q: duplicate key", mod.Key)
+		}
+		seen[mod.Key] = true
+
+		if mod.Cost < 0 || mod.Mass < 0 {
+			return fmt.Errorf("module %q: cost and mass must not be negative", mod.Key)
+		}
+		if mod.StatModifier == "" && mod.StatValue != 0 {
+			return fmt.Errorf("module %q: stat_value set without stat_modifier", mod.Key)
+		}
+		if mod.Tier > 0 && mod.Line == "" {
+			return fmt.Errorf("module %q: tier set without line", mod.Key)
+		}
+		if mod.MaxStack < 0 {
+			return fmt.Errorf("module %q: max_stack must not be negative", mod.Key)
+		}
+		for _, e := range ModuleEffects(mod) {
+			if !IsShipStat(e.Stat) {
+				return fmt.Errorf("module %q: unknown stat modifier %q", mod.Key, e.Stat)
+			}
+			if e.Mult < 0 {
+				return fmt.Errorf("module %q: negative multiplier for %q", mod.Key, e.Stat)
+			}
+		}
+	}
+
+	// Prerequisites may only reference modules that exist
+	for _, mod := range u.ShipModules {
+		for _, req := range mod.Requires {
+			if !seen[req] {
+				return fmt.Errorf("module %q: requires unknown module %q", mod.Key, req)
+			}
+		}
+	}
+	return nil
+}
+
+// ModuleEffects returns every effect of a module, including the StatModifier/StatValue shorthand.
+func ModuleEffects(mod ShipModule) []ModuleEffect {
+	effects := []ModuleEffect{}
+	if mod.StatModifier != "" {
+		effects = append(effects, ModuleEffect{Stat: mod.StatModifier, Add: mod.StatValue})
+	}
+	return append(effects, mod.Effects...)
+}
+
+// ComputeShipStats derives the effective stats of a chassis fitted with 'modules'.
+// Formula (per stat): (Base + Sum(Add)) * Product(Mult)
+func ComputeShipStats(base ShipStats, modules []ShipModule) ShipStats {
+	adds := make(map[string]float64)
+	mults := make(map[string]float64)
+
+	for _, mod := range modules {
+		for _, e := range ModuleEffects(mod) {
+			adds[e.Stat] += float64(e.Add)
+			if e.Mult != 0 {
+				if _, ok := mults[e.Stat]; !ok {
+					mults[e.Stat] = 1.0
+				}
+				mults[e.Stat] *= e.Mult
+			}
+		}
+	}
+
+	apply := func(key string, value float64) float64 {
+		value += adds[key]
+		if m, ok := mults[key]; ok {
+			value *= m
+		}
+		return math.Max(0, math.Round(value))
+	}
+
+	stats := base
+	for key, field := range statFields(&stats) {
+		switch f := field.(type) {
+		case *int:
+			*f = int(apply(key, float64(*f)))
+		case *int64:
+			*f = int64(apply(key, float64(*f)))
+		}
+	}
+
+	// Damping divides the mass penalty, so it can never reach zero
+	if stats.BurnDamping < 1 {
+		stats.BurnDamping = 1
+	}
+	return stats
+}
+
+// CalculateModuleMass sums the weight of every installed module.
+func CalculateModuleMass(ship *Ship) int64 {
+	var total int64
+	for _, mod := range ship.InstalledModules {
+		total += int64(mod.Mass)
+	}
+	return total
+}
+
+// RefreshShipStats recomputes ship.Effective from its chassis and installed modules.
+// Must be called whenever Base or InstalledModules change.
+// Note: Caller must hold DataLock
+func RefreshShipStats(ship *Ship) {
+	ship.Effective = ComputeShipStats(ship.Base, ship.InstalledModules)
+
+	// A smaller tank cannot hold more fuel than it fits
+	if ship.Fuel > ship.Effective.MaxFuel {
+		ship.Fuel = ship.Effective.MaxFuel
+	}
+}
+
+// SyncInstalledModules replaces the stored copies of every module with the current
+// catalog definition, so a hot reload of module values reaches ships already fitted.
+// Modules missing from the catalog keep their last known definition. The price paid is kept.
+// Note: Caller must hold DataLock
+func SyncInstalledModules(ship *Ship) {
+	for _, list := range [][]ShipModule{ship.InstalledModules, ship.StoredModules} {
+		for i := range list {
+			if mod := GetModule(list[i].Key); mod != nil {
+				paid := list[i].PaidPrice
+				list[i] = *mod
+				list[i].PaidPrice = paid
+			}
+		}
+	}
+}
+
+// ModuleResaleValue returns the refund paid by the shipyard when selling a module.
+// It is based on what the owner paid, not the catalog Cost, so discounted modules
+// never sell back for more than they were bought for.
+func ModuleResaleValue(mod ShipModule) int {
+	return resaleValue(mod.PaidPrice)
+}
+
+// resaleValue applies ModuleResaleRate to a purchase price.
+func resaleValue(cost int) int {
+	rate := CurrentUniverse.BalanceConfig.ModuleResaleRate
+	if rate <= 0 {
+		rate = 0.5
+	}
+	return int(math.Floor(float64(cost) * rate))
+}
+
+// planInstall evaluates every upgrade tree rule for fitting 'mod' onto a ship.
+// Returns the indexes of installed lower-tier modules that 'mod' replaces, plus every failed rule.
+// Career gates (reputation, earnings) only apply to purchases (buyer != nil), not to re-installing owned modules.
+func planInstall(ship *Ship, mod ShipModule, buyer *Player) (replaced []int, problems []error) {
+	fail := func(err error) {
+		for _, p := range problems {
+			if p == err {
+				return
+			}
+		}
+		problems = append(problems, err)
+	}
+
+	copies := 0
+	for i, m := range ship.InstalledModules {
+		if m.Key == mod.Key {
+			copies++
+		}
+		sameLine := mod.Line != "" && m.Line == mod.Line
+		if sameLine {
+			if m.Tier < mod.Tier {
+				replaced = append(replaced, i)
+			} else {
+				fail(ErrModuleObsolete)
+			}
+			continue
+		}
+		if mod.ExclusiveGroup != "" && m.ExclusiveGroup == mod.ExclusiveGroup && m.Key != mod.Key {
+			fail(ErrModuleExclusive)
+		}
+	}
+	if mod.MaxStack > 0 && copies >= mod.MaxStack {
+		fail(ErrModuleMaxStack)
+	}
+
+	for _, req := range mod.Requires {
+		if !hasModuleOrUpgrade(ship.InstalledModules, req) {
+			fail(ErrModulePrerequisite)
+		}
+	}
+	if buyer != nil && buyer.Reputation < mod.MinReputation {
+		fail(ErrReputationTooLow)
+	}
+	if buyer != nil && buyer.LifetimeEarnings < mod.MinEarnings {
+		fail(ErrEarningsTooLow)
+	}
+	if buyer != nil && mod.Faction != "" && buyer.Standing[mod.Faction] < mod.MinStanding {
+		fail(ErrStandingTooLow)
+	}
+
+	// Check the ship as it would be after the swap
+	after := []ShipModule{}
+	for i, m := range ship.InstalledModules {
+		if !containsIndex(replaced, i) {
+			after = append(after, m)
+		}
+	}
+	after = append(after, mod)
+	stats := ComputeShipStats(ship.Base, after)
+
+	if len(after) > stats.MaxModuleSlots {
+		fail(ErrNoModuleSlots)
+	}
+	cargo, passengers := CalculateLoad(ship)
+	if cargo > stats.CargoCapacity || passengers > stats.PassengerSlots {
+		fail(ErrCapacityInUse)
+	}
+	if ship.Fuel > stats.MaxFuel {
+		fail(ErrTankInUse)
+	}
+	if RequiredComfort(ship) > stats.Comfort {
+		fail(ErrComfortInUse)
+	}
+	if carriesHazardous(ship) && !hasContainment(after) { // Defined in traits.go
+		fail(ErrContainmentInUse)
+	}
+	return replaced, problems
+}
+
+// hasModuleOrUpgrade reports whether 'key' (or a higher tier of its line) is in 'list'.
+func hasModuleOrUpgrade(list []ShipModule, key string) bool {
+	want := GetModule(key)
+	for _, m := range list {
+		if m.Key == key {
+			return true
+		}
+		if want != nil && want.Line != "" && m.Line == want.Line && m.Tier >= want.Tier {
+			return true
+		}
+	}
+	return false
+}
+
+// BuyModule purchases a module from the local shipyard and installs it on the player's active ship.
+// Lower tiers of the same line are traded in at their resale value.
+// Note: Caller must hold DataLock
+func BuyModule(p *Player, key string) error {
+	ship := p.ActiveShip()
+	mod := GetModule(key)
+	if mod == nil {
+		return ErrModuleNotFound
+	}
+	price, sold := ShipyardPrice(ship.LocationKey, *mod)
+	if !sold {
+		return ErrModuleNotSold
+	}
+
+	replaced, problems := planInstall(ship, *mod, p)
+	if len(problems) > 0 {
+		return problems[0]
+	}
+
+	tradeIn := 0
+	for _, idx := range replaced {
+		tradeIn += ModuleResaleValue(ship.InstalledModules[idx])
+	}
+	if p.Credits+tradeIn < price {
+		return ErrInsufficientCredits
+	}
+
+	p.Credits += tradeIn - price
+	recordLedger(p, LedgerModuleBuy, tradeIn-price, mod.Key, fmt.Sprintf("Bought %s (price %d, trade-in %d)", mod.Name, price, tradeIn))
+	bought := *mod
+	bought.PaidPrice = price
+	ship.InstalledModules = append(removeModules(ship.InstalledModules, replaced), bought)
+	RefreshShipStats(ship)
+	return nil
+}
+
+// OfferModules describes every module sold by the local shipyard from the player's point of view,
+// so the client can render the upgrade tree with locked/unlocked states. Cost is the local price.
+// Note: Caller must hold DataLock
+func OfferModules(p *Player) []ModuleOffer {
+	ship := p.ActiveShip()
+	offers := []ModuleOffer{}
+	for _, mod := range CurrentUniverse.ShipModules {
+		price, sold := ShipyardPrice(ship.LocationKey, mod)
+		if !sold {
+			continue
+		}
+		mod.Cost = price
+		replaced, problems := planInstall(ship, mod, p)
+
+		offer := ModuleOffer{ShipModule: mod}
+		tradeIn := 0
+		for _, idx := range replaced {
+			tradeIn += ModuleResaleValue(ship.InstalledModules[idx])
+			offer.Replaces = append(offer.Replaces, ship.InstalledModules[idx].Key)
+		}
+		if p.Credits+tradeIn < mod.Cost {
+			problems = append(problems, ErrInsufficientCredits)
+		}
+		for _, p := range problems {
+			offer.Reasons = append(offer.Reasons, p.Error())
+		}
+		for _, m := range ship.InstalledModules {
+			if m.Key == mod.Key {
+				offer.Installed++
+			}
+		}
+		offer.Available = len(problems) == 0
+		offers = append(offers, offer)
+	}
+	return offers
+}
+
+// UninstallModule removes one installed module by key and moves it into storage.
+// Refuses if the lost capacity is still occupied by active contracts.
+// Note: Caller must hold DataLock
+func UninstallModule(ship *Ship, key string) error {
+	idx := findModule(ship.InstalledModules, key)
+	if idx == -1 {
+		return ErrModuleNotInstalled
+	}
+	mod := ship.InstalledModules[idx]
+
+	if err := checkRemoval(ship, idx); err != nil {
+		return err
+	}
+
+	ship.InstalledModules = removeModule(ship.InstalledModules, idx)
+	ship.StoredModules = append(ship.StoredModules, mod)
+	RefreshShipStats(ship)
+	return nil
+}
+
+// InstallModule moves one stored module by key back into a free module slot.
+// Lower tiers of the same line are swapped out into storage.
+// Note: Caller must hold DataLock
+func InstallModule(ship *Ship, key string) error {
+	idx := findModule(ship.StoredModules, key)
+	if idx == -1 {
+		return ErrModuleNotStored
+	}
+
+	mod := ship.StoredModules[idx]
+	replaced, problems := planInstall(ship, mod, nil)
+	if len(problems) > 0 {
+		return problems[0]
+	}
+
+	ship.StoredModules = removeModule(ship.StoredModules, idx)
+	for _, r := range replaced {
+		ship.StoredModules = append(ship.StoredModules, ship.InstalledModules[r])
+	}
+	ship.InstalledModules = append(removeModules(ship.InstalledModules, replaced), mod)
+	RefreshShipStats(ship)
+	return nil
+}
+
+// SellModule sells one module of the player's active ship by key, either from storage (stored = true)
+// or straight out of its slot, and credits the resale value. Returns the refund paid.
+// Note: Caller must hold DataLock
+func SellModule(p *Player, key string, stored bool) (int, error) {
+	ship := p.ActiveShip()
+	var mod ShipModule
+	if stored {
+		idx := findModule(ship.StoredModules, key)
+		if idx == -1 {
+			return 0, ErrModuleNotStored
+		}
+		mod = ship.StoredModules[idx]
+		ship.StoredModules = removeModule(ship.StoredModules, idx)
+	} else {
+		idx := findModule(ship.InstalledModules, key)
+		if idx == -1 {
+			return 0, ErrModuleNotInstalled
+		}
+		mod = ship.InstalledModules[idx]
+		if err := checkRemoval(ship, idx); err != nil {
+			return 0, err
+		}
+		ship.InstalledModules = removeModule(ship.InstalledModules, idx)
+		RefreshShipStats(ship)
+	}
+
+	refund := ModuleResaleValue(mod)
+	p.Credits += refund
+	recordLedger(p, LedgerModuleSell, refund, mod.Key, "Sold "+mod.Name)
+	return refund, nil
+}
+
+// checkRemoval ensures the ship still fits its current load, fuel and modules
+// once the installed module at idx is gone. Modules that require it must go first.
+func checkRemoval(ship *Ship, idx int) error {
+	remaining := removeModule(ship.InstalledModules, idx)
+	after := ComputeShipStats(ship.Base, remaining)
+
+	for _, m := range remaining {
+		for _, req := range m.Requires {
+			if !hasModuleOrUpgrade(remaining, req) {
+				return ErrModuleRequired
+			}
+		}
+	}
+
+	cargo, passengers := CalculateLoad(ship)
+	if cargo > after.CargoCapacity || passengers > after.PassengerSlots {
+		return ErrCapacityInUse
+	}
+	if ship.Fuel > after.MaxFuel {
+		return ErrTankInUse
+	}
+	if len(remaining) > after.MaxModuleSlots {
+		return ErrSlotsInUse
+	}
+	if RequiredComfort(ship) > after.Comfort {
+		return ErrComfortInUse
+	}
+	if carriesHazardous(ship) && !hasContainment(remaining) { // Defined in traits.go
+		return ErrContainmentInUse
+	}
+	return nil
+}
+
+// findModule returns the index of the first module with the given key, or -1.
+func findModule(list []ShipModule, key string) int {
+	for i, m := range list {
+		if m.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeModule deletes the module at idx without aliasing the original slice.
+func removeModule(list []ShipModule, idx int) []ShipModule {
+	out := make([]ShipModule, 0, len(list)-1)
+	out = append(out, list[:idx]...)
+	return append(out, list[idx+1:]...)
+}
+
+// removeModules deletes every module whose index is listed in idxs.
+func removeModules(list []ShipModule, idxs []int) []ShipModule {
+	out := make([]ShipModule, 0, len(list))
+	for i, m := range list {
+		if !containsIndex(idxs, i) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// containsIndex reports whether idx is present in idxs.
+func containsIndex(idxs []int, idx int) bool {
+	for _, i := range idxs {
+		if i == idx {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,278 @@
+/*
+Package game
+File: npc.go
+Description:
+    Simulates NPC traders that compete with players for contracts.
+
+    Without them, job boards are only drained by players and sit at MaxCargo
+    when nobody is online. NPC traders alternate between two actions on the
+    heartbeat: take a batch of contracts (chosen by their strategy), then fly
+    them to their destination. Both go through travel.go, so acceptances and
+    deliveries heat the market exactly like player activity does.
+*/
+
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// NPC trading strategies.
+const (
+	NPCGreedy  = "greedy"  // Destination with the highest total payout
+	NPCNearest = "nearest" // Closest destination with any job
+	NPCRandom  = "random"  // Destination of a random job
+)
+
+// RunNPCTraders advances every NPC trader by one action.
+// Called by the heartbeat in main.go. Returns the number of contracts delivered.
+func RunNPCTraders() int {
+	DataLock.Lock()
+	defer DataLock.Unlock()
+
+	delivered := 0
+	for _, t := range Traders {
+		delivered += stepTrader(t)
+	}
+	return delivered
+}
+
+// SpawnNPCTraders rebuilds the NPC fleet from the 'npc_traders' configuration.
+// Traders start at random planets with the starting credits.
+// Note: Caller must hold DataLock
+func SpawnNPCTraders() {
+	Traders = []*NPCTrader{}
+	if len(CurrentUniverse.Planets) == 0 {
+		return
+	}
+
+	for _, cfg := range CurrentUniverse.NPCTraders {
+		hullKey := cfg.Hull
+		if hullKey == "" {
+			hullKey = StartingHullKey()
+		}
+		hull := GetHull(hullKey)
+		if hull == nil {
+			continue
+		}
+
+		for i := 0; i < cfg.Count; i++ {
+			id := fmt.Sprintf("NPC-%d", len(Traders)+1)
+			planet := CurrentUniverse.Planets[rand.Intn(len(CurrentUniverse.Planets))]
+
+			ship := newShip(hull, planet.Key)
+			ship.Name = fmt.Sprintf("%s %d", cfg.Name, i+1)
+
+			wallet := &Player{ID: id, Credits: CurrentUniverse.BalanceConfig.StartingCredits, ActiveShipID: ship.ID, Fleet: []*Ship{ship}}
+			recordLedger(wallet, LedgerOpening, wallet.Credits, "", "Starting credits")
+
+			Traders = append(Traders, &NPCTrader{
+				ID:       id,
+				Name:     ship.Name,
+				Strategy: cfg.Strategy,
+				Wallet:   wallet,
+				Ship:     ship,
+			})
+		}
+	}
+}
+
+// stepTrader performs one action: deliver the cargo on board, or pick up new contracts.
+// Returns the number of contracts delivered.
+func stepTrader(t *NPCTrader) int {
+	ship := t.Ship
+
+	// 1. Loaded: Fly to the destination of the oldest contract
+	if len(ship.ActiveContracts) > 0 {
+		dest := ship.ActiveContracts[0].DestinationKey
+		if !traderCanReach(ship, dest) {
+			refuelTrader(t)
+		}
+		if !traderCanReach(ship, dest) {
+			// Stranded (no depot or no money): NPCs get towed instead of clogging the board
+			ship.Fuel = ship.Effective.MaxFuel
+			t.Tows++
+		}
+		if !traderCanReach(ship, dest) {
+			// Even a full tank can't lift this load (e.g. after a config reload): abandon it
+			for _, c := range ship.ActiveContracts {
+				forfeitCollateral(t.Wallet, c, "abandoned by NPC trader") // Defined in escrow.go
+			}
+			ship.ActiveContracts = []Contract{}
+			return 0
+		}
+
+		result, err := TravelShip(t.Wallet, ship, dest)
+		if err != nil {
+			return 0
+		}
+		t.Deliveries += len(result.Delivered)
+		t.Earnings += result.Payout
+		return len(result.Delivered)
+	}
+
+	// 2. Empty: Top up while docked, then take a batch of jobs to one destination
+	refuelTrader(t)
+
+	dest := chooseTraderDestination(t)
+	if dest == "" {
+		// Nothing worth taking here: reposition empty to another planet
+		relocateTrader(t)
+		return 0
+	}
+
+	jobs := []Contract{}
+	for _, c := range AvailableContracts[ship.LocationKey] {
+		if c.DestinationKey == dest && c.PostedBy == "" {
+			jobs = append(jobs, c)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Payout > jobs[j].Payout })
+	for _, c := range jobs {
+		if !traderCanLift(ship, c) {
+			continue // Too heavy to reach the destination even on a full tank
+		}
+		AcceptContract(t.Wallet, ship, c.ID) // Jobs that don't fit are simply skipped
+	}
+	return 0
+}
+
+// traderCanLift reports whether the ship could still reach the contract's destination
+// on a full tank after loading it.
+func traderCanLift(ship *Ship, c Contract) bool {
+	origin := GetPlanet(ship.LocationKey)
+	dest := GetPlanet(c.DestinationKey)
+	if origin == nil || dest == nil {
+		return false
+	}
+
+	ship.ActiveContracts = append(ship.ActiveContracts, c)
+	burn := CalculateRouteBurn(ship, ship.Effective.MaxFuel, dest.Key)
+	ship.ActiveContracts = ship.ActiveContracts[:len(ship.ActiveContracts)-1]
+
+	return ship.Effective.MaxFuel >= CalculateDistance(origin.Coordinates, dest.Coordinates)*burn
+}
+
+// chooseTraderDestination picks where the trader's next batch goes, based on its strategy.
+// Only jobs that fit into the empty hold and can be flown on a full tank are considered.
+// Player postings are left to players, so their escrow is never lost with an NPC's cargo.
+// Returns "" if there are none.
+func chooseTraderDestination(t *NPCTrader) string {
+	ship := t.Ship
+	origin := GetPlanet(ship.LocationKey)
+	if origin == nil {
+		return ""
+	}
+
+	payouts := make(map[string]int)
+	keys := []string{}
+	for _, c := range AvailableContracts[ship.LocationKey] {
+		if c.PostedBy != "" || !c.AvailableTo(t.Wallet) || checkStanding(t.Wallet, c) != nil ||
+			(c.Type == "cargo" && c.Quantity > ship.Effective.CargoCapacity) ||
+			(c.Type == "passenger" && c.Quantity > ship.Effective.PassengerSlots) ||
+			c.ComfortRequired > ship.Effective.Comfort ||
+			checkCargoRules(ship, c) != nil ||
+			!traderCanLift(ship, c) {
+			continue
+		}
+		if _, seen := payouts[c.DestinationKey]; !seen {
+			keys = append(keys, c.DestinationKey)
+		}
+		payouts[c.DestinationKey] += c.Payout
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+
+	switch t.Strategy {
+	case NPCGreedy:
+		best := keys[0]
+		for _, k := range keys[1:] {
+			if payouts[k] > payouts[best] {
+				best = k
+			}
+		}
+		return best
+	case NPCNearest:
+		best, bestDist := "", int64(-1)
+		for _, k := range keys {
+			dest := GetPlanet(k)
+			if dest == nil {
+				continue
+			}
+			if d := CalculateDistance(origin.Coordinates, dest.Coordinates); bestDist < 0 || d < bestDist {
+				best, bestDist = k, d
+			}
+		}
+		return best
+	default: // NPCRandom
+		return keys[rand.Intn(len(keys))]
+	}
+}
+
+// relocateTrader flies an empty trader to a random planet within range.
+func relocateTrader(t *NPCTrader) {
+	options := []string{}
+	for _, p := range CurrentUniverse.Planets {
+		if p.Key != t.Ship.LocationKey && traderCanReach(t.Ship, p.Key) {
+			options = append(options, p.Key)
+		}
+	}
+	if len(options) == 0 {
+		return
+	}
+	TravelShip(t.Wallet, t.Ship, options[rand.Intn(len(options))])
+}
+
+// traderCanReach reports whether the ship has enough fuel to fly to 'destKey' at its current mass.
+func traderCanReach(ship *Ship, destKey string) bool {
+	origin := GetPlanet(ship.LocationKey)
+	dest := GetPlanet(destKey)
+	if origin == nil || dest == nil {
+		return false
+	}
+	return ship.Fuel >= CalculateDistance(origin.Coordinates, dest.Coordinates)*CalculateRouteBurn(ship, ship.Fuel, destKey)
+}
+
+// refuelTrader fills the tank at the local depot, as far as the trader's credits allow.
+func refuelTrader(t *NPCTrader) {
+	quote, err := PlanRefuel(t.Wallet, t.Ship, 0, 0)
+	if err != nil {
+		return
+	}
+	if !quote.CanAfford {
+		if t.Wallet.Credits <= 0 {
+			return
+		}
+		if quote, err = PlanRefuel(t.Wallet, t.Ship, 0, t.Wallet.Credits); err != nil {
+			return
+		}
+	}
+	BuyFuel(t.Wallet, t.Ship, quote)
+}
+
+// ValidateNPCs checks the 'npc_traders' configuration.
+// The trader hull must exist, so this runs after ValidateHulls.
+func ValidateNPCs(u *Universe) error {
+	hulls := make(map[string]bool)
+	for _, h := range u.Hulls {
+		hulls[h.Key] = true
+	}
+
+	for _, cfg := range u.NPCTraders {
+		switch cfg.Strategy {
+		case NPCGreedy, NPCNearest, NPCRandom:
+		default:
+			return fmt.Errorf("npc_traders %q: unknown strategy %q", cfg.Name, cfg.Strategy)
+		}
+		if cfg.Count < 0 {
+			return fmt.Errorf("npc_traders %q: negative count", cfg.Name)
+		}
+		if cfg.Hull != "" && !hulls[cfg.Hull] {
+			return fmt.Errorf("npc_traders %q: unknown hull %q", cfg.Name, cfg.Hull)
+		}
+	}
+	return nil
+}
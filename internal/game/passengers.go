@@ -0,0 +1,141 @@
+/*
+Package game
+File: passengers.go
+Description:
+    Defines the passenger classes and their rules.
+
+    Classes (economy, VIP, group charters, refugees...) are configured in
+    'universe.yaml' under passenger_config. Each has its own fare formula,
+    party size, weight and comfort requirement; the ship's comfort rating
+    comes from its hull and installed modules.
+*/
+
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// DefaultPassengerPerLY is the fare per LY of the implicit economy class
+// used when 'passenger_config' defines no classes.
+const DefaultPassengerPerLY = 15
+
+// PassengerClasses returns the configured classes, or a single economy class
+// built from the base passenger config.
+func PassengerClasses() []PassengerClass {
+	cfg := CurrentUniverse.PassengerConfig
+	if len(cfg.Classes) > 0 {
+		return cfg.Classes
+	}
+	return []PassengerClass{{
+		Key:      "pax_economy",
+		Name:     "Passenger",
+		Weight:   1,
+		BaseFare: cfg.BaseTicketPrice,
+		PerLY:    DefaultPassengerPerLY,
+	}}
+}
+
+// pickPassengerClass chooses a weighted random class that may board at the origin.
+// Returns nil if no class is allowed there.
+func pickPassengerClass(originKey string) *PassengerClass {
+	eligible := []PassengerClass{}
+	total := 0
+	for _, c := range PassengerClasses() {
+		if c.Weight <= 0 || !boardsAt(c, originKey) {
+			continue
+		}
+		eligible = append(eligible, c)
+		total += c.Weight
+	}
+	if total == 0 {
+		return nil
+	}
+
+	roll := rand.Intn(total)
+	for i := range eligible {
+		roll -= eligible[i].Weight
+		if roll < 0 {
+			return &eligible[i]
+		}
+	}
+	return nil
+}
+
+// boardsAt reports whether passengers of a class board at the given planet.
+func boardsAt(c PassengerClass, planetKey string) bool {
+	if len(c.Origins) == 0 {
+		return true
+	}
+	for _, k := range c.Origins {
+		if k == planetKey {
+			return true
+		}
+	}
+	return false
+}
+
+// partySize rolls the number of passengers travelling together.
+func partySize(c PassengerClass) int {
+	lo := max(c.MinQuantity, 1)
+	hi := max(c.MaxQuantity, lo)
+	return lo + rand.Intn(hi-lo+1)
+}
+
+// PassengerFare computes the payout for a party of 'qty' passengers travelling 'dist' LY.
+func PassengerFare(c PassengerClass, dist int64, qty int) int {
+	return (c.BaseFare + c.PerLY*int(dist)) * qty
+}
+
+// PassengerMass returns the weight of one passenger of a contract.
+func PassengerMass(c Contract) int {
+	if c.MassPerUnit > 0 {
+		return c.MassPerUnit
+	}
+	return CurrentUniverse.PassengerConfig.MassPerPassenger
+}
+
+// RequiredComfort returns the highest comfort rating demanded by the passengers on board.
+func RequiredComfort(ship *Ship) int {
+	required := 0
+	for _, c := range ship.ActiveContracts {
+		required = max(required, c.ComfortRequired)
+	}
+	return required
+}
+
+// ValidatePassengers checks the passenger classes.
+func ValidatePassengers(u *Universe) error {
+	planets := make(map[string]bool)
+	terminals := make(map[string]bool)
+	for _, p := range u.Planets {
+		planets[p.Key] = true
+		terminals[p.Key] = slices.Contains(p.Services, ServicePassengerTerminal)
+	}
+
+	keys := make(map[string]bool)
+	for _, c := range u.PassengerConfig.Classes {
+		if c.Key == "" || keys[c.Key] {
+			return fmt.Errorf("passenger class %q: missing or duplicate key", c.Key)
+		}
+		keys[c.Key] = true
+
+		if c.Weight < 0 || c.BaseFare < 0 || c.PerLY < 0 || c.Mass < 0 || c.ComfortRequirement < 0 {
+			return fmt.Errorf("passenger class %q: values must not be negative", c.Key)
+		}
+		if c.MaxQuantity > 0 && c.MaxQuantity < c.MinQuantity {
+			return fmt.Errorf("passenger class %q: max_quantity below min_quantity", c.Key)
+		}
+		for _, k := range c.Origins {
+			if !planets[k] {
+				return fmt.Errorf("passenger class %q: unknown origin %q", c.Key, k)
+			}
+			if !terminals[k] {
+				return fmt.Errorf("passenger class %q: origin %q has no passenger terminal", c.Key, k)
+			}
+		}
+	}
+	return nil
+}
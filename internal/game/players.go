@@ -0,0 +1,309 @@
+/*
+Package game
+File: players.go
+Description:
+    Manages players and their fleets.
+    This includes:
+    1. Registering players (a fresh ship at the starting planet and a session token).
+    2. Buying additional hulls at shipyards.
+    3. Switching the active ship while docked.
+
+    The wallet and career live on the Player; fuel, cargo and modules live on
+    each Ship, so a player can own several differently fitted vessels.
+*/
+
+package game
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Fleet validation errors.
+var (
+	ErrHullNotFound     = errors.New("hull not found")
+	ErrHullNotSold      = errors.New("hull not sold at this shipyard")
+	ErrShipNotFound     = errors.New("ship not found in fleet")
+	ErrShipNotDocked    = errors.New("ship is not docked at the current location")
+	ErrShipAlreadyFlown = errors.New("ship is already the active ship")
+	ErrContractsAboard  = errors.New("active ship still carries contracts")
+	ErrTransferCapacity = errors.New("target ship cannot hold the transferred contracts")
+	ErrPlayerExists     = errors.New("player already registered")
+)
+
+// ActiveShip returns the ship the player is currently flying.
+func (p *Player) ActiveShip() *Ship {
+	return p.GetShip(p.ActiveShipID)
+}
+
+// GetShip returns a ship of the player's fleet by ID, or nil.
+func (p *Player) GetShip(id string) *Ship {
+	for _, s := range p.Fleet {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// GetPlayer returns a registered player, or nil.
+// Note: Caller must hold DataLock
+func GetPlayer(id string) *Player {
+	return Players[id]
+}
+
+// PlayerByToken returns the player a session token was issued to, or nil.
+// Note: Caller must hold DataLock
+func PlayerByToken(token string) *Player {
+	if token == "" {
+		return nil
+	}
+	return Players[PlayerTokens[token]]
+}
+
+// RegisterPlayer creates a new player and issues their session token (p.Token).
+// Fails if the ID is already taken.
+// Note: Caller must hold DataLock
+func RegisterPlayer(id string) (*Player, error) {
+	if Players[id] != nil {
+		return nil, ErrPlayerExists
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewPlayer(id)
+	p.Token = token
+	PlayerTokens[token] = id
+	return p, nil
+}
+
+// newToken returns a random 128-bit session token in hex.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := cryptorand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// NewPlayer creates and registers a player with the starting hull, credits and location.
+// Note: Caller must hold DataLock
+func NewPlayer(id string) *Player {
+	p := &Player{
+		ID:      id,
+		Credits: CurrentUniverse.BalanceConfig.StartingCredits,
+		Fleet:   []*Ship{},
+	}
+	recordLedger(p, LedgerOpening, p.Credits, "", "Starting credits")
+
+	if hull := GetHull(StartingHullKey()); hull != nil {
+		ship := newShip(hull, StartingPlanetKey())
+		p.Fleet = append(p.Fleet, ship)
+		p.ActiveShipID = ship.ID
+	}
+
+	Players[id] = p
+	return p
+}
+
+// GetHull is a helper to retrieve a HullClass pointer by its Key.
+func GetHull(key string) *HullClass {
+	for _, h := range CurrentUniverse.Hulls {
+		if h.Key == key {
+			return &h
+		}
+	}
+	return nil
+}
+
+// StartingHullKey returns the hull new players start with.
+// Falls back to the first hull if 'starting_hull' is not configured.
+func StartingHullKey() string {
+	if key := CurrentUniverse.BalanceConfig.StartingHull; key != "" {
+		return key
+	}
+	if len(CurrentUniverse.Hulls) > 0 {
+		return CurrentUniverse.Hulls[0].Key
+	}
+	return ""
+}
+
+// newShip builds a fully fueled, empty ship from a hull.
+func newShip(hull *HullClass, locationKey string) *Ship {
+	ship := &Ship{
+		ID:               fmt.Sprintf("SHP-%d-%d", rand.Intn(99999), time.Now().UnixNano()%1000),
+		Name:             hull.Name,
+		HullKey:          hull.Key,
+		LocationKey:      locationKey,
+		Base:             hull.Stats,
+		InstalledModules: []ShipModule{},
+		StoredModules:    []ShipModule{},
+		ActiveContracts:  []Contract{},
+	}
+	RefreshShipStats(ship)
+	ship.Fuel = ship.Effective.MaxFuel
+	return ship
+}
+
+// HullPrice returns the local price of a hull at a planet's shipyard.
+// The second return value is false if the shipyard does not sell the hull.
+func HullPrice(planetKey string, hull HullClass) (int, bool) {
+	planet := GetPlanet(planetKey)
+	if planet == nil || !HasService(planetKey, ServiceShipyard) {
+		return 0, false
+	}
+
+	mult := 1.0
+	if planet.Shipyard != nil {
+		if planet.Shipyard.PriceMult > 0 {
+			mult = planet.Shipyard.PriceMult
+		}
+		if len(planet.Shipyard.Hulls) > 0 {
+			sold := false
+			for _, k := range planet.Shipyard.Hulls {
+				if k == hull.Key {
+					sold = true
+					break
+				}
+			}
+			if !sold {
+				return 0, false
+			}
+		}
+	}
+	return int(float64(hull.Cost) * mult), true
+}
+
+// OfferHulls lists every hull sold at the player's current location, with local prices.
+// Note: Caller must hold DataLock
+func OfferHulls(p *Player) []HullOffer {
+	offers := []HullOffer{}
+	for _, hull := range CurrentUniverse.Hulls {
+		price, sold := HullPrice(p.ActiveShip().LocationKey, hull)
+		if !sold {
+			continue
+		}
+		offers = append(offers, HullOffer{HullClass: hull, Price: price, CanAfford: p.Credits >= price})
+	}
+	return offers
+}
+
+// BuyHull purchases a new ship at the local shipyard and adds it to the player's fleet.
+// The new ship is delivered fueled and docked next to the active ship, but not flown.
+// Note: Caller must hold DataLock
+func BuyHull(p *Player, hullKey, name string) (*Ship, error) {
+	hull := GetHull(hullKey)
+	if hull == nil {
+		return nil, ErrHullNotFound
+	}
+
+	location := p.ActiveShip().LocationKey
+	price, sold := HullPrice(location, *hull)
+	if !sold {
+		return nil, ErrHullNotSold
+	}
+	if p.Credits < price {
+		return nil, ErrInsufficientCredits
+	}
+
+	ship := newShip(hull, location)
+	if name != "" {
+		ship.Name = name
+	}
+
+	p.Credits -= price
+	recordLedger(p, LedgerHullBuy, -price, hull.Key, fmt.Sprintf("Bought %s (%s)", ship.Name, ship.ID))
+	p.Fleet = append(p.Fleet, ship)
+	return ship, nil
+}
+
+// SwitchShip makes another ship of the fleet the active one. Both ships must be docked
+// at the same planet. Contracts on board must be delivered first, or moved across
+// (transfer = true) if the target ship has room for them. A route on the target is cancelled.
+// Note: Caller must hold DataLock
+func SwitchShip(p *Player, shipID string, transfer bool) error {
+	current := p.ActiveShip()
+	target := p.GetShip(shipID)
+	if target == nil {
+		return ErrShipNotFound
+	}
+	if target == current {
+		return ErrShipAlreadyFlown
+	}
+	if target.LocationKey != current.LocationKey {
+		return ErrShipNotDocked
+	}
+
+	if len(current.ActiveContracts) > 0 {
+		if !transfer {
+			return ErrContractsAboard
+		}
+
+		cargo, passengers := CalculateLoad(current)
+		targetCargo, targetPassengers := CalculateLoad(target)
+		if targetCargo+cargo > target.Effective.CargoCapacity ||
+			targetPassengers+passengers > target.Effective.PassengerSlots ||
+			RequiredComfort(current) > target.Effective.Comfort {
+			return ErrTransferCapacity
+		}
+		for _, c := range current.ActiveContracts {
+			if err := checkCargoRules(target, c); err != nil { // Defined in traits.go
+				return err
+			}
+		}
+
+		target.ActiveContracts = append(target.ActiveContracts, current.ActiveContracts...)
+		current.ActiveContracts = []Contract{}
+	}
+
+	// Taking the helm ends the ship's automated orders
+	if target.Route != nil {
+		target.Route = nil
+		logRoute(target, "cancel", 0, "Captain took the helm, route cancelled")
+	}
+
+	p.ActiveShipID = target.ID
+	return nil
+}
+
+// ValidateHulls checks the hull catalog and every reference to it.
+func ValidateHulls(u *Universe) error {
+	if len(u.Hulls) == 0 {
+		return errors.New("no hulls defined")
+	}
+
+	hulls := make(map[string]bool)
+	for _, h := range u.Hulls {
+		if h.Key == "" {
+			return fmt.Errorf("hull %q: missing key", h.Name)
+		}
+		if hulls[h.Key] {
+			return fmt.Errorf("hull %q: duplicate key", h.Key)
+		}
+		if h.Stats.BurnDamping <= 0 || h.Stats.MaxFuel <= 0 {
+			return fmt.Errorf("hull %q: max_fuel and burn_damping must be positive", h.Key)
+		}
+		hulls[h.Key] = true
+	}
+
+	if start := u.BalanceConfig.StartingHull; start != "" && !hulls[start] {
+		return fmt.Errorf("starting_hull %q is not a hull", start)
+	}
+	for _, p := range u.Planets {
+		if p.Shipyard == nil {
+			continue
+		}
+		for _, k := range p.Shipyard.Hulls {
+			if !hulls[k] {
+				return fmt.Errorf("planet %q: shipyard lists unknown hull %q", p.Key, k)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+/*
+Package game
+File: postings.go
+Description:
+    Contracts posted to job boards by players.
+
+    A player can hand a cargo contract from their hold to the job board of
+    the planet they are docked at, offering a payout of their own. The payout
+    is taken into escrow, the goods leave the hold, and the posting is listed
+    alongside generated jobs (flagged with the poster) for anyone to accept.
+    - Delivered: The hauler is paid from the escrow; the poster completes
+      their original contract (payout, reputation, collateral refund).
+    - Failed (dropped, expired, lost or seized): The poster gets the escrow
+      back but loses the original contract as if they had dropped it.
+    - Withdrawn: While still on the board, the poster can take the goods back.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Posting errors.
+var (
+	ErrNotPostable     = errors.New("only single-stop cargo contracts of your own can be posted")
+	ErrAlreadyAtTarget = errors.New("contract destination is this planet")
+	ErrOwnPosting      = errors.New("cannot haul your own posting")
+)
+
+// PostContract moves a cargo contract from the active ship's hold to the local job board,
+// offering 'payout' credits (held in escrow) to whoever delivers it.
+// Note: Caller must hold DataLock
+func PostContract(p *Player, contractID string, payout int) (Contract, error) {
+	ship := p.ActiveShip()
+	idx := findContract(ship.ActiveContracts, contractID)
+	if idx == -1 {
+		return Contract{}, ErrContractNotFound
+	}
+	c := ship.ActiveContracts[idx]
+	if c.Type != "cargo" || len(c.Stops) > 0 || c.Chain != nil || c.ContractorID != "" || c.PostedBy != "" {
+		return Contract{}, ErrNotPostable
+	}
+	if c.DestinationKey == ship.LocationKey {
+		return Contract{}, ErrAlreadyAtTarget
+	}
+	if payout <= 0 {
+		return Contract{}, ErrInvalidAmount
+	}
+	if p.Credits < payout {
+		return Contract{}, ErrInsufficientCredits
+	}
+	if err := checkOutgoing(p, payout); err != nil { // Defined in trade.go
+		return Contract{}, err
+	}
+
+	recordOutgoing(p, payout)
+	original := c
+	posting := c
+	posting.ID = newContractID("PST")
+	posting.OriginKey = ship.LocationKey
+	posting.Payout = payout
+	posting.Collateral = 0 // The poster's collateral stays in escrow until the goods arrive
+	posting.PostedBy = p.ID
+	posting.Original = &original
+	posting.Faction = "" // Standing with the issuing faction stays with the poster
+	posting.MinStanding = 0
+
+	p.Credits -= payout
+	p.Escrow += payout
+	recordLedger(p, LedgerPostingHold, -payout, posting.ID, fmt.Sprintf("Posted %d %s for delivery to %s", c.Quantity, c.ItemName, c.DestinationKey))
+
+	ship.ActiveContracts = append(ship.ActiveContracts[:idx], ship.ActiveContracts[idx+1:]...)
+	AvailableContracts[ship.LocationKey] = append(AvailableContracts[ship.LocationKey], posting)
+	return posting, nil
+}
+
+// WithdrawPosting takes a posting that nobody accepted back into the active ship's hold
+// and refunds the escrowed payout. The ship must be docked at the posting's board.
+// Note: Caller must hold DataLock
+func WithdrawPosting(p *Player, postingID string) (Contract, error) {
+	ship := p.ActiveShip()
+	board := AvailableContracts[ship.LocationKey]
+	idx := findContract(board, postingID)
+	if idx == -1 || board[idx].PostedBy != p.ID {
+		return Contract{}, ErrContractNotFound
+	}
+	posting := board[idx]
+	original := *posting.Original
+	original.Travelled = posting.Travelled
+	if err := canLoad(ship, original); err != nil {
+		return Contract{}, err
+	}
+
+	refundPosting(p, posting)
+	AvailableContracts[ship.LocationKey] = append(board[:idx], board[idx+1:]...)
+	ship.ActiveContracts = append(ship.ActiveContracts, original)
+	return original, nil
+}
+
+// refundPosting returns the escrowed payout of a posting to its poster.
+func refundPosting(poster *Player, posting Contract) {
+	poster.Credits += posting.Payout
+	poster.Escrow -= posting.Payout
+	recordLedger(poster, LedgerPostingRefund, posting.Payout, posting.ID, "Posting payout returned")
+}
+
+// refundPostingDecay returns the part of a perishable posting's escrowed payout that the
+// hauler did not earn because the goods decayed in flight.
+func refundPostingDecay(c Contract, escrowed int) {
+	poster := Players[c.PostedBy]
+	lost := escrowed - c.Payout
+	if poster == nil || lost <= 0 {
+		return
+	}
+	poster.Credits += lost
+	poster.Escrow -= lost
+	recordLedger(poster, LedgerPostingRefund, lost, c.ID, "Posting payout lost to spoilage returned")
+}
+
+// settlePosting completes a delivered posting for its poster: the hauler's payout leaves the
+// escrow and the poster is paid for their original contract. Called by payDelivery (travel.go).
+func settlePosting(c Contract) {
+	poster := Players[c.PostedBy]
+	if poster == nil || c.Original == nil {
+		return
+	}
+	poster.Escrow -= c.Payout
+	recordLedger(poster, LedgerPostingPaid, 0, c.ID, fmt.Sprintf("Posting delivered, %d paid to the hauler from escrow", c.Payout))
+
+	original := *c.Original
+	original.Travelled = c.Travelled
+	payout := DeliveryValue(original)
+	poster.Credits += payout
+	poster.LifetimeEarnings += payout
+	poster.Reputation += ReputationPerDelivery + original.BonusReputation
+	rewardStanding(poster, original)
+	recordLedger(poster, LedgerDelivery, payout, original.ID, fmt.Sprintf("Delivered %d %s to %s (via posting %s)", original.Quantity, original.ItemName, original.DestinationKey, c.ID))
+	releaseCollateral(poster, original)
+}
+
+// failPosting settles a posting whose goods never arrive: the poster gets the escrowed
+// payout back but loses the original contract (reputation and collateral).
+func failPosting(c Contract, reason string) {
+	poster := Players[c.PostedBy]
+	if poster == nil || c.Original == nil {
+		return
+	}
+	refundPosting(poster, c)
+	poster.Reputation -= ReputationPerDrop
+	penalizeStanding(poster, *c.Original)
+	forfeitCollateral(poster, *c.Original, reason)
+}
+
+// expirePostings removes postings whose deadline passed while still on a job board.
+// Called by ExpireContracts (escrow.go). Returns the number removed.
+func expirePostings() int {
+	now := time.Now().Unix()
+	expired := 0
+	for key, board := range AvailableContracts {
+		remaining := []Contract{}
+		for _, c := range board {
+			if c.PostedBy == "" || c.Deadline == 0 || now <= c.Deadline {
+				remaining = append(remaining, c)
+				continue
+			}
+			failPosting(c, "posting expired on the board")
+			expired++
+		}
+		AvailableContracts[key] = remaining
+	}
+	return expired
+}
@@ -0,0 +1,311 @@
+/*
+Package game
+File: routes.go
+Description:
+    Runs automated hauling routes for secondary ships.
+
+    A player can hand any ship other than the one they are flying a list of
+    orders (accept matching contracts, travel, refuel). The heartbeat calls
+    RunRoutes, which executes one step per ship per tick through the same
+    operations the API uses (travel.go), so capacity, fuel and delivery rules
+    are identical to manual play.
+
+    Every step is written to the ship's log, and revenue/fuel spend is
+    accumulated into a per-ship profit report.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Route step actions.
+const (
+	RouteAccept = "accept"
+	RouteTravel = "travel"
+	RouteRefuel = "refuel"
+)
+
+// Route status values.
+const (
+	RouteRunning  = "running"
+	RouteStalled  = "stalled"
+	RouteFinished = "finished"
+)
+
+// Route limits.
+const (
+	MaxRouteSteps = 16  // Longest accepted route
+	MaxRouteLog   = 100 // Log entries kept per ship (oldest dropped first)
+)
+
+// Route errors.
+var (
+	ErrInvalidRoute = errors.New("invalid route")
+	ErrShipIsActive = errors.New("the active ship is flown by hand")
+	ErrNoRoute      = errors.New("ship has no route")
+)
+
+// ValidateRoute checks a list of steps before it is assigned to a ship.
+func ValidateRoute(steps []RouteStep) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("%w: no steps", ErrInvalidRoute)
+	}
+	if len(steps) > MaxRouteSteps {
+		return fmt.Errorf("%w: more than %d steps", ErrInvalidRoute, MaxRouteSteps)
+	}
+
+	for i, s := range steps {
+		switch s.Action {
+		case RouteTravel:
+			if GetPlanet(s.PlanetKey) == nil {
+				return fmt.Errorf("%w: step %d: unknown planet %q", ErrInvalidRoute, i+1, s.PlanetKey)
+			}
+		case RouteAccept:
+			f := s.Filter
+			if f.Type != "" && f.Type != "cargo" && f.Type != "passenger" {
+				return fmt.Errorf("%w: step %d: unknown contract type %q", ErrInvalidRoute, i+1, f.Type)
+			}
+			if f.DestinationKey != "" && GetPlanet(f.DestinationKey) == nil {
+				return fmt.Errorf("%w: step %d: unknown planet %q", ErrInvalidRoute, i+1, f.DestinationKey)
+			}
+			if f.MinPayout < 0 || f.MaxContracts < 0 {
+				return fmt.Errorf("%w: step %d: negative filter value", ErrInvalidRoute, i+1)
+			}
+		case RouteRefuel:
+			if s.Budget < 0 {
+				return fmt.Errorf("%w: step %d: negative budget", ErrInvalidRoute, i+1)
+			}
+		default:
+			return fmt.Errorf("%w: step %d: unknown action %q", ErrInvalidRoute, i+1, s.Action)
+		}
+	}
+	return nil
+}
+
+// AssignRoute gives a secondary ship new orders, replacing any previous route.
+// The ship starts with the first step on the next tick.
+// Note: Caller must hold DataLock
+func AssignRoute(p *Player, shipID string, steps []RouteStep, repeat bool) (*Ship, error) {
+	ship := p.GetShip(shipID)
+	if ship == nil {
+		return nil, ErrShipNotFound
+	}
+	if ship.ID == p.ActiveShipID {
+		return nil, ErrShipIsActive
+	}
+	if err := ValidateRoute(steps); err != nil {
+		return nil, err
+	}
+
+	ship.Route = &RouteOrder{Steps: steps, Repeat: repeat, Status: RouteRunning}
+	logRoute(ship, "assign", 0, fmt.Sprintf("New route with %d steps", len(steps)))
+	return ship, nil
+}
+
+// CancelRoute clears a ship's orders. It stays docked where it is.
+// Note: Caller must hold DataLock
+func CancelRoute(p *Player, shipID string) (*Ship, error) {
+	ship := p.GetShip(shipID)
+	if ship == nil {
+		return nil, ErrShipNotFound
+	}
+	if ship.Route == nil {
+		return nil, ErrNoRoute
+	}
+
+	ship.Route = nil
+	logRoute(ship, "cancel", 0, "Route cancelled")
+	return ship, nil
+}
+
+// RunRoutes executes the next step of every running route.
+// Called by the heartbeat in main.go. Returns the number of steps executed.
+func RunRoutes() int {
+	DataLock.Lock()
+	defer DataLock.Unlock()
+
+	steps := 0
+	for _, p := range Players {
+		for _, ship := range p.Fleet {
+			// The active ship belongs to its captain, even if it still carries old orders
+			if ship.ID == p.ActiveShipID || ship.Route == nil || ship.Route.Status != RouteRunning {
+				continue
+			}
+			runRouteStep(p, ship)
+			steps++
+		}
+	}
+	return steps
+}
+
+// runRouteStep executes the current step of a ship's route and advances the cursor.
+// A failing step stalls the route until the player assigns new orders.
+func runRouteStep(p *Player, ship *Ship) {
+	route := ship.Route
+	step := route.Steps[route.Cursor]
+
+	var err error
+	switch step.Action {
+	case RouteAccept:
+		err = routeAccept(p, ship, step.Filter)
+	case RouteTravel:
+		err = routeTravel(p, ship, step.PlanetKey)
+	case RouteRefuel:
+		err = routeRefuel(p, ship, step.Budget)
+	default:
+		err = fmt.Errorf("%w: unknown action %q", ErrInvalidRoute, step.Action)
+	}
+
+	if err != nil {
+		route.Status = RouteStalled
+		route.Reason = err.Error()
+		logRoute(ship, step.Action, 0, "Route stalled: "+err.Error())
+		return
+	}
+
+	route.Cursor++
+	if route.Cursor < len(route.Steps) {
+		return
+	}
+	if route.Repeat {
+		route.Cursor = 0
+		ship.RouteReport.Loops++
+		return
+	}
+	route.Status = RouteFinished
+	logRoute(ship, step.Action, 0, "Route finished")
+}
+
+// routeAccept takes every contract on the local board that matches the filter and fits in the hold.
+// Finding nothing is not an error; the ship simply moves on.
+func routeAccept(p *Player, ship *Ship, f ContractFilter) error {
+	// Work on a snapshot: AcceptContract modifies the board
+	board := append([]Contract(nil), AvailableContracts[ship.LocationKey]...)
+
+	taken, payout := 0, 0
+	for _, c := range board {
+		if f.MaxContracts > 0 && taken >= f.MaxContracts {
+			break
+		}
+		if !f.Matches(c) {
+			continue
+		}
+		if _, err := AcceptContract(p, ship, c.ID); err != nil {
+			continue // Does not fit; a smaller job further down might
+		}
+		taken++
+		payout += c.Payout
+	}
+
+	logRoute(ship, RouteAccept, 0, fmt.Sprintf("Accepted %d contracts worth %d credits", taken, payout))
+	return nil
+}
+
+// routeTravel flies to the next planet and books the delivery revenue.
+func routeTravel(p *Player, ship *Ship, destKey string) error {
+	result, err := TravelShip(p, ship, destKey)
+	if err != nil {
+		return err
+	}
+
+	report := &ship.RouteReport
+	report.Revenue += result.Payout
+	report.Fines += result.Fine
+	report.CorpTax += result.CorpTax
+	report.Losses += len(result.Lost) + len(result.Confiscated)
+	report.Profit += result.Payout - result.Fine - result.CorpTax
+	report.Deliveries += len(result.Delivered)
+	report.Distance += result.Distance
+	report.FuelBurned += result.FuelUsed
+
+	msg := fmt.Sprintf("Arrived after %d LY, delivered %d contracts", result.Distance, len(result.Delivered))
+	if n := len(result.Lost); n > 0 {
+		msg += fmt.Sprintf(", %d lost in flight", n)
+	}
+	if n := len(result.Confiscated); n > 0 {
+		msg += fmt.Sprintf(", %d seized by customs (fine %d)", n, result.Fine)
+	}
+	if result.CorpTax > 0 {
+		msg += fmt.Sprintf(", %d corporation tax", result.CorpTax)
+	}
+	logRoute(ship, RouteTravel, result.Payout-result.Fine-result.CorpTax, msg)
+	return nil
+}
+
+// routeRefuel tops up the tank (within the budget, if set). A full tank is not an error.
+func routeRefuel(p *Player, ship *Ship, budget int) error {
+	quote, err := PlanRefuel(p, ship, 0, budget)
+	if errors.Is(err, ErrTankFull) {
+		logRoute(ship, RouteRefuel, 0, "Tank already full")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := BuyFuel(p, ship, quote); err != nil {
+		return err
+	}
+
+	report := &ship.RouteReport
+	report.FuelCost += quote.Cost
+	report.Profit -= quote.Cost
+
+	logRoute(ship, RouteRefuel, -quote.Cost, fmt.Sprintf("Bought %.2f fuel", float64(quote.Amount)/FuelUnitScale))
+	return nil
+}
+
+// Matches reports whether a contract passes the filter.
+func (f ContractFilter) Matches(c Contract) bool {
+	return (f.Type == "" || c.Type == f.Type) &&
+		(f.ItemKey == "" || c.ItemKey == f.ItemKey) &&
+		(f.DestinationKey == "" || c.DestinationKey == f.DestinationKey) &&
+		c.Payout >= f.MinPayout
+}
+
+// logRoute appends an entry to the ship's route log, dropping the oldest beyond MaxRouteLog.
+func logRoute(ship *Ship, action string, credits int, msg string) {
+	ship.RouteLog = append(ship.RouteLog, RouteLogEntry{
+		Timestamp:   time.Now().Unix(),
+		Action:      action,
+		LocationKey: ship.LocationKey,
+		Message:     msg,
+		Credits:     credits,
+	})
+	if len(ship.RouteLog) > MaxRouteLog {
+		ship.RouteLog = ship.RouteLog[len(ship.RouteLog)-MaxRouteLog:]
+	}
+}
+
+// BuildFleetReport summarizes the automated operation of every ship in the fleet.
+// Note: Caller must hold DataLock
+func BuildFleetReport(p *Player) FleetReport {
+	report := FleetReport{Ships: []ShipReport{}}
+	for _, ship := range p.Fleet {
+		report.Ships = append(report.Ships, ShipReport{
+			ShipID:      ship.ID,
+			Name:        ship.Name,
+			LocationKey: ship.LocationKey,
+			Active:      ship.ID == p.ActiveShipID,
+			Route:       ship.Route,
+			Report:      ship.RouteReport,
+		})
+
+		t := &report.Total
+		r := ship.RouteReport
+		t.Revenue += r.Revenue
+		t.FuelCost += r.FuelCost
+		t.Fines += r.Fines
+		t.CorpTax += r.CorpTax
+		t.Losses += r.Losses
+		t.Profit += r.Profit
+		t.Deliveries += r.Deliveries
+		t.Distance += r.Distance
+		t.FuelBurned += r.FuelBurned
+		t.Loops += r.Loops
+	}
+	return report
+}
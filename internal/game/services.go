@@ -0,0 +1,159 @@
+/*
+Package game
+File: services.go
+Description:
+    Handles the docking services offered by planets.
+
+    Planets declare their services in 'universe.yaml' (shipyard, fuel depot,
+    passenger terminal, customs, bank). Every location-dependent rule asks
+    this file instead of hardcoding planet keys, so the map can be redesigned
+    purely in data.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Service keys used in the 'services' list of a planet.
+const (
+	ServiceShipyard          = "shipyard"
+	ServiceFuelDepot         = "fuel_depot"
+	ServicePassengerTerminal = "passenger_terminal"
+	ServiceCustoms           = "customs" // Inspects arriving ships for contraband (traits.go)
+	ServiceBank              = "bank"    // Grants and collects loans (bank.go)
+)
+
+// Default depot configuration applied when a planet lists "fuel_depot" without a 'fuel_depot' block.
+const (
+	DefaultDepotCapacity = 100000
+	DefaultDepotRestock  = 5000
+)
+
+// ErrModuleNotSold is returned when the local shipyard does not stock a module.
+var ErrModuleNotSold = errors.New("module not sold at this shipyard")
+
+// HasService reports whether the planet offers the given service.
+func HasService(planetKey, service string) bool {
+	planet := GetPlanet(planetKey)
+	if planet == nil {
+		return false
+	}
+	for _, s := range planet.Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// StartingPlanetKey returns the spawn location for new players.
+// Falls back to the first planet if 'starting_planet' is not configured.
+func StartingPlanetKey() string {
+	if key := CurrentUniverse.BalanceConfig.StartingPlanet; key != "" {
+		return key
+	}
+	if len(CurrentUniverse.Planets) > 0 {
+		return CurrentUniverse.Planets[0].Key
+	}
+	return ""
+}
+
+// ShipyardPrice returns the local price of a module at a planet's shipyard.
+// The second return value is false if the shipyard does not sell the module.
+// Any local price is safe: modules sell back at a fraction of what was paid (see ModuleResaleValue).
+func ShipyardPrice(planetKey string, mod ShipModule) (int, bool) {
+	planet := GetPlanet(planetKey)
+	if planet == nil || !HasService(planetKey, ServiceShipyard) {
+		return 0, false
+	}
+	// Faction modules are only sold inside the faction's territory
+	if mod.Faction != "" && mod.Faction != planet.Faction {
+		return 0, false
+	}
+
+	mult := 1.0
+	var listings []ModuleListing
+	if planet.Shipyard != nil {
+		if planet.Shipyard.PriceMult > 0 {
+			mult = planet.Shipyard.PriceMult
+		}
+		listings = planet.Shipyard.Modules
+	}
+
+	// No explicit catalog: the shipyard sells everything
+	if len(listings) == 0 {
+		return int(float64(mod.Cost) * mult), true
+	}
+	for _, l := range listings {
+		if l.Key != mod.Key {
+			continue
+		}
+		if l.Cost > 0 {
+			return l.Cost, true
+		}
+		return int(float64(mod.Cost) * mult), true
+	}
+	return 0, false
+}
+
+// ValidatePlanets checks service declarations and fills in default service configuration.
+func ValidatePlanets(u *Universe) error {
+	known := map[string]bool{
+		ServiceShipyard:          true,
+		ServiceFuelDepot:         true,
+		ServicePassengerTerminal: true,
+		ServiceCustoms:           true,
+		ServiceBank:              true,
+	}
+	modules := make(map[string]bool)
+	for _, m := range u.ShipModules {
+		modules[m.Key] = true
+	}
+
+	planets := make(map[string]bool)
+	for i := range u.Planets {
+		p := &u.Planets[i]
+		planets[p.Key] = true
+
+		offers := make(map[string]bool)
+		for _, s := range p.Services {
+			if !known[s] {
+				return fmt.Errorf("planet %q: unknown service %q", p.Key, s)
+			}
+			offers[s] = true
+		}
+
+		// Service configuration blocks only make sense with the matching service
+		if p.FuelDepot != nil && !offers[ServiceFuelDepot] {
+			return fmt.Errorf("planet %q: fuel_depot configured but %q not in services", p.Key, ServiceFuelDepot)
+		}
+		if p.Shipyard != nil && !offers[ServiceShipyard] {
+			return fmt.Errorf("planet %q: shipyard configured but %q not in services", p.Key, ServiceShipyard)
+		}
+
+		if offers[ServiceFuelDepot] && p.FuelDepot == nil {
+			p.FuelDepot = &FuelDepot{PriceMult: 1.0, Capacity: DefaultDepotCapacity, Restock: DefaultDepotRestock}
+		}
+		if p.Shipyard != nil {
+			if p.Shipyard.PriceMult < 0 {
+				return fmt.Errorf("planet %q: shipyard price_mult must not be negative", p.Key)
+			}
+			for _, l := range p.Shipyard.Modules {
+				if !modules[l.Key] {
+					return fmt.Errorf("planet %q: shipyard lists unknown module %q", p.Key, l.Key)
+				}
+				if l.Cost < 0 {
+					return fmt.Errorf("planet %q: shipyard price of %q must not be negative", p.Key, l.Key)
+				}
+			}
+		}
+	}
+
+	if start := u.BalanceConfig.StartingPlanet; start != "" && !planets[start] {
+		return fmt.Errorf("starting_planet %q is not a planet", start)
+	}
+	return nil
+}
@@ -4,7 +4,7 @@ File: state.go
 Description:
     Manages the runtime state of the application.
     It holds the Global Variables that represent the current universe,
-    the player's ship, and the active job board.
+    the players (and their fleets), the NPC traders, and the active job board.
 
     It also handles the initialization (LoadConfig) logic.
 */
@@ -28,9 +28,12 @@ var (
 	// CurrentUniverse holds the static configuration loaded from YAML.
 	CurrentUniverse Universe
 
-	// PlayerShip represents the specific instance of the player's vessel.
-	// This is modified heavily during runtime (travel, trading, upgrades).
-	PlayerShip Ship
+	// Players maps PlayerID -> Player (wallet, career and fleet).
+	// Ships are modified heavily during runtime (travel, trading, upgrades).
+	Players = make(map[string]*Player)
+
+	// PlayerTokens maps session token -> PlayerID. Filled by RegisterPlayer.
+	PlayerTokens = make(map[string]string)
 
 	// AvailableContracts maps PlanetKey -> List of Contracts.
 	// These are the jobs currently sitting on the "Job Board" at each planet.
@@ -40,11 +43,21 @@ var (
 	Market = MarketState{
 		SourceHeat: make(map[string]map[string]float64),
 		DestHeat:   make(map[string]map[string]float64),
+		FuelStock:  make(map[string]int64),
 	}
+
+	// ActiveEvents lists the galactic events currently in effect.
+	ActiveEvents []ActiveEvent
+
+	// Traders holds the simulated NPC haulers. Rebuilt on every (re)load.
+	Traders []*NPCTrader
+
+	// MarketHistory stores a bounded time-series of Market heat, one snapshot per tick.
+	MarketHistory MarketHistoryBuffer
 )
 
 // LoadConfig reads 'universe.yaml' and initializes the game state.
-// It resets the player to the default configuration if they don't have a location.
+// On a hot reload, existing ships are re-derived from the new hull and module definitions.
 func LoadConfig() error {
 	DataLock.Lock()
 	defer DataLock.Unlock()
@@ -60,25 +73,72 @@ func LoadConfig() error {
 	if err := yaml.Unmarshal(f, &newUni); err != nil {
 		return err
 	}
+
+	// Reject broken definitions before they replace the live universe
+	if err := ValidateModules(&newUni); err != nil {
+		return err
+	}
+	if err := ValidateTraits(&newUni); err != nil { // Defined in traits.go
+		return err
+	}
+	if err := ValidatePlanets(&newUni); err != nil { // Defined in services.go
+		return err
+	}
+	if err := ValidateHulls(&newUni); err != nil { // Defined in players.go
+		return err
+	}
+	if err := ValidateNPCs(&newUni); err != nil { // Defined in npc.go
+		return err
+	}
+	if err := ValidateEvents(&newUni); err != nil { // Defined in events.go
+		return err
+	}
+	if err := ValidatePassengers(&newUni); err != nil { // Defined in passengers.go
+		return err
+	}
+	if err := ValidateContractShapes(&newUni); err != nil { // Defined in contracts.go
+		return err
+	}
+	if err := ValidateEscrow(&newUni); err != nil { // Defined in escrow.go
+		return err
+	}
+	if err := ValidateBanking(&newUni); err != nil { // Defined in bank.go
+		return err
+	}
+	if err := ValidateFactions(&newUni); err != nil { // Defined in factions.go
+		return err
+	}
+	if err := ValidateCorporations(&newUni); err != nil { // Defined in corporations.go
+		return err
+	}
+	if err := ValidateAchievements(&newUni); err != nil { // Defined in achievements.go
+		return err
+	}
 	CurrentUniverse = newUni
 
 	// 3. Initialize the Market Heat Maps
 	InitMarket() // Defined in economy.go
+	MarketHistory.Resize(CurrentUniverse.BalanceConfig.MarketHistorySize)
 
 	// 4. Initialize Random Seed for procedural generation
 	// We do this once here to ensure random distribution throughout the session.
 	rand.Seed(time.Now().UnixNano())
 
-	// 5. Initialize Player Ship (New Game Logic)
-	// If the ship has no location, we assume it's a fresh boot and apply defaults.
-	if PlayerShip.LocationKey == "" {
-		PlayerShip = CurrentUniverse.PlayerShipConfig
-		PlayerShip.Fuel = PlayerShip.MaxFuel
-		PlayerShip.LocationKey = "planet_prime"
-		PlayerShip.Credits = CurrentUniverse.BalanceConfig.StartingCredits
-		PlayerShip.ActiveContracts = []Contract{}
-		PlayerShip.InstalledModules = []ShipModule{}
+	// 5. Refresh Existing Fleets (Hot-Reload)
+	// New players are created on demand by NewPlayer; existing ships pick up the
+	// (possibly changed) hull and module definitions.
+	for _, p := range Players {
+		for _, ship := range p.Fleet {
+			if hull := GetHull(ship.HullKey); hull != nil {
+				ship.Base = hull.Stats
+			}
+			SyncInstalledModules(ship)
+			RefreshShipStats(ship)
+		}
 	}
 
+	// 6. Respawn NPC Traders (contracts they carried are lost)
+	SpawnNPCTraders() // Defined in npc.go
+
 	return nil
 }
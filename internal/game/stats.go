@@ -0,0 +1,235 @@
+/*
+Package game
+File: stats.go
+Description:
+    Player statistics and leaderboards.
+
+    The travel, accept and delivery paths feed every player's statistics
+    through recordStats. Each change is applied twice: to the all-time
+    totals and to an hourly bucket, so the last week can be queried in
+    time windows ("hour", "day", "week"). Windows are made of whole clock
+    hours, ending with the current, still running one, so "hour" is the
+    current clock hour. Buckets older than a week are dropped, keeping
+    memory bounded.
+
+    Leaderboards rank all players by one metric in one window; net worth
+    is always the current value (credits, escrow and the resale value of
+    ships and modules, minus debt).
+*/
+
+package game
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Stats windows and limits.
+const (
+	StatsHistoryHours      = 7 * 24 // Hourly buckets kept per player (including the current one)
+	DefaultLeaderboardSize = 10
+	MaxLeaderboardSize     = 100
+	LeaderboardPulseTicks  = 5 // Economy ticks between leaderboard broadcasts
+	LeaderboardPulseSize   = 5 // Entries per metric in a broadcast
+)
+
+// Leaderboard metrics.
+const (
+	MetricNetWorth   = "net_worth"
+	MetricEarnings   = "earnings"
+	MetricDistance   = "distance"
+	MetricFuel       = "fuel_burned"
+	MetricDeliveries = "deliveries"
+	MetricCargo      = "cargo"
+	MetricPassengers = "passengers"
+)
+
+// leaderboardMetrics lists every metric in the order they are broadcast.
+var leaderboardMetrics = []string{MetricNetWorth, MetricEarnings, MetricDistance, MetricFuel, MetricDeliveries, MetricCargo, MetricPassengers}
+
+// statsWindows maps the window names to their length in hours (0 = all time).
+var statsWindows = map[string]int64{"all": 0, "hour": 1, "day": 24, "week": StatsHistoryHours}
+
+// Stats errors.
+var (
+	ErrUnknownMetric = errors.New("unknown leaderboard metric")
+	ErrUnknownWindow = errors.New("unknown stats window")
+)
+
+// initMaps allocates the breakdown maps of a fresh set of statistics.
+func (s *PlayerStats) initMaps() {
+	if s.DeliveredByType == nil {
+		s.DeliveredByType = make(map[string]int)
+		s.UnitsDelivered = make(map[string]int)
+		s.DeliveriesTo = make(map[string]int)
+	}
+}
+
+// addDelivery counts one delivered contract (or stop) and what it earned.
+func (s *PlayerStats) addDelivery(c Contract, earned int) {
+	s.initMaps()
+	s.Deliveries++
+	s.DeliveredByType[c.Type]++
+	s.UnitsDelivered[c.ItemKey] += c.Quantity
+	s.DeliveriesTo[c.DestinationKey]++
+	s.Earnings += earned
+}
+
+// merge adds another set of statistics to this one.
+func (s *PlayerStats) merge(o PlayerStats) {
+	s.initMaps()
+	s.Distance += o.Distance
+	s.FuelBurned += o.FuelBurned
+	s.Trips += o.Trips
+	s.Accepted += o.Accepted
+	s.Deliveries += o.Deliveries
+	s.Earnings += o.Earnings
+	for kind, n := range o.DeliveredByType {
+		s.DeliveredByType[kind] += n
+	}
+	for item, n := range o.UnitsDelivered {
+		s.UnitsDelivered[item] += n
+	}
+	for planet, n := range o.DeliveriesTo {
+		s.DeliveriesTo[planet] += n
+	}
+}
+
+// recordStats applies a change to the player's all-time statistics and to the current hourly bucket.
+func recordStats(p *Player, change func(s *PlayerStats)) {
+	change(&p.Stats)
+
+	hour := time.Now().Unix() / 3600
+	if n := len(p.StatsHistory); n == 0 || p.StatsHistory[n-1].Hour != hour {
+		p.StatsHistory = append(p.StatsHistory, StatsBucket{Hour: hour})
+		if len(p.StatsHistory) > StatsHistoryHours {
+			p.StatsHistory = p.StatsHistory[len(p.StatsHistory)-StatsHistoryHours:]
+		}
+	}
+	change(&p.StatsHistory[len(p.StatsHistory)-1].Stats)
+}
+
+// StatsFor returns the player's statistics within a window ("all", "hour", "day" or "week").
+// A window of N hours holds N clock hours: the current one and the N-1 before it.
+// Note: Caller must hold DataLock
+func StatsFor(p *Player, window string) (PlayerStats, error) {
+	hours, ok := statsWindows[window]
+	if !ok {
+		return PlayerStats{}, ErrUnknownWindow
+	}
+	total := PlayerStats{}
+	if hours == 0 {
+		total.merge(p.Stats)
+		return total, nil
+	}
+
+	since := time.Now().Unix()/3600 - hours
+	for _, b := range p.StatsHistory {
+		if b.Hour > since {
+			total.merge(b.Stats)
+		}
+	}
+	return total, nil
+}
+
+// NetWorthOf values everything the player owns: credits and escrow, plus ships and modules
+// at their resale value, minus debt.
+// Note: Caller must hold DataLock
+func NetWorthOf(p *Player) NetWorth {
+	nw := NetWorth{Credits: p.Credits, Escrow: p.Escrow, Debt: p.Debt}
+	for _, ship := range p.Fleet {
+		if hull := GetHull(ship.HullKey); hull != nil {
+			nw.Ships += resaleValue(hull.Cost)
+		}
+		for _, m := range ship.InstalledModules {
+			nw.Modules += ModuleResaleValue(m)
+		}
+		for _, m := range ship.StoredModules {
+			nw.Modules += ModuleResaleValue(m)
+		}
+	}
+	nw.Total = nw.Credits + nw.Escrow + nw.Ships + nw.Modules - nw.Debt
+	return nw
+}
+
+// BuildStatsReport collects the player's statistics in every window plus their net worth.
+// Note: Caller must hold DataLock
+func BuildStatsReport(p *Player) StatsReport {
+	report := StatsReport{Windows: make(map[string]PlayerStats), NetWorth: NetWorthOf(p)}
+	for window := range statsWindows {
+		report.Windows[window], _ = StatsFor(p, window)
+	}
+	return report
+}
+
+// metricValue extracts one leaderboard metric from a player's statistics.
+func metricValue(p *Player, s PlayerStats, metric string) int64 {
+	switch metric {
+	case MetricNetWorth:
+		return int64(NetWorthOf(p).Total)
+	case MetricEarnings:
+		return int64(s.Earnings)
+	case MetricDistance:
+		return s.Distance
+	case MetricFuel:
+		return s.FuelBurned
+	case MetricDeliveries:
+		return int64(s.Deliveries)
+	case MetricCargo:
+		return int64(s.DeliveredByType["cargo"])
+	case MetricPassengers:
+		return int64(s.DeliveredByType["passenger"])
+	}
+	return 0
+}
+
+// Leaderboard ranks every player by a metric within a window. A limit of 0 selects
+// DefaultLeaderboardSize; larger limits are capped at MaxLeaderboardSize.
+// Net worth ignores the window (it is always the current value).
+// Note: Caller must hold DataLock
+func Leaderboard(metric, window string, limit int) ([]LeaderboardEntry, error) {
+	if !slices.Contains(leaderboardMetrics, metric) {
+		return nil, ErrUnknownMetric
+	}
+	if _, ok := statsWindows[window]; !ok {
+		return nil, ErrUnknownWindow
+	}
+	if limit <= 0 {
+		limit = DefaultLeaderboardSize
+	}
+	limit = min(limit, MaxLeaderboardSize)
+
+	entries := []LeaderboardEntry{}
+	for _, p := range Players {
+		stats, _ := StatsFor(p, window)
+		entries = append(entries, LeaderboardEntry{PlayerID: p.ID, CorpID: p.CorpID, Value: metricValue(p, stats, metric)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return entries[i].Value > entries[j].Value
+		}
+		return entries[i].PlayerID < entries[j].PlayerID
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries, nil
+}
+
+// LeaderboardPulse builds the top of every leaderboard for the last day.
+// Called by the heartbeat in main.go every LeaderboardPulseTicks ticks.
+func LeaderboardPulse() map[string][]LeaderboardEntry {
+	DataLock.RLock()
+	defer DataLock.RUnlock()
+
+	pulse := make(map[string][]LeaderboardEntry)
+	for _, metric := range leaderboardMetrics {
+		pulse[metric], _ = Leaderboard(metric, "day", LeaderboardPulseSize)
+	}
+	return pulse
+}
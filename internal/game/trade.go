@@ -0,0 +1,323 @@
+/*
+Package game
+File: trade.go
+Description:
+    Exchanges between players.
+    This includes:
+    1. Credit Transfers: Wiring credits to another player, anywhere.
+       Everything of value a player sends to others (transfers, trade credits
+       and modules, corp deposits, posting payouts) needs TransferMinEarnings
+       of lifetime earnings first and counts against TransferDailyCap, so
+       fresh accounts cannot be farmed for their starting credits. Players
+       in debt cannot send anything until they have repaid it.
+    2. Trade Offers: A handshake between two captains docked at the same planet.
+       The offering player proposes credits, contracts and stored modules (and
+       may ask for credits in return); the recipient accepts or declines.
+    3. Subcontracting: Contracts handed over in a trade stay linked to the
+       original contractor, who receives the agreed share of the payout on delivery.
+
+    Accepting an offer re-checks everything and then moves all items in one
+    step under the DataLock, so a trade either completes fully or not at all.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Trade errors.
+var (
+	ErrPlayerNotFound    = errors.New("player not found")
+	ErrSelfTrade         = errors.New("cannot trade with yourself")
+	ErrOfferNotFound     = errors.New("trade offer not found")
+	ErrNotDockedTogether = errors.New("both players must be docked at the same planet")
+	ErrEmptyOffer        = errors.New("trade offer contains nothing")
+	ErrInvalidShare      = errors.New("share must be between 0 and 100")
+	ErrNotTransferable   = errors.New("contract is already subcontracted")
+	ErrTransfersLocked   = errors.New("not enough lifetime earnings to send value to other players")
+	ErrTransferCap       = errors.New("daily transfer limit reached")
+)
+
+// TradeOffers holds the pending offers by ID.
+// Note: Access must be guarded by DataLock
+var TradeOffers = make(map[string]*TradeOffer)
+
+// TransferCredits wires credits from one player to another.
+// Note: Caller must hold DataLock
+func TransferCredits(from *Player, toID string, amount int) error {
+	to := Players[toID]
+	if to == nil {
+		return ErrPlayerNotFound
+	}
+	if to == from {
+		return ErrSelfTrade
+	}
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if from.Credits < amount {
+		return ErrInsufficientCredits
+	}
+	if err := checkOutgoing(from, amount); err != nil {
+		return err
+	}
+
+	recordOutgoing(from, amount)
+	from.Credits -= amount
+	to.Credits += amount
+	recordLedger(from, LedgerTransferOut, -amount, to.ID, fmt.Sprintf("Transfer to %s", to.ID))
+	recordLedger(to, LedgerTransferIn, amount, from.ID, fmt.Sprintf("Transfer from %s", from.ID))
+	return nil
+}
+
+// OfferTrade proposes an exchange to another player docked at the same planet.
+// The items stay with the offering player until the recipient accepts.
+// Note: Caller must hold DataLock
+func OfferTrade(from *Player, offer TradeOffer) (*TradeOffer, error) {
+	offer.FromID = from.ID
+	if len(offer.Contracts) == 0 && len(offer.Modules) == 0 && offer.Credits == 0 && offer.RequestCredits == 0 {
+		return nil, ErrEmptyOffer
+	}
+	if offer.Credits < 0 || offer.RequestCredits < 0 {
+		return nil, ErrInvalidAmount
+	}
+	if offer.Share < 0 || offer.Share > 100 {
+		return nil, ErrInvalidShare
+	}
+	if _, _, err := checkTrade(&offer); err != nil {
+		return nil, err
+	}
+
+	offer.ID = fmt.Sprintf("TRD-%d-%d", rand.Intn(99999), time.Now().UnixNano()%1000)
+	offer.CreatedAt = time.Now().Unix()
+	TradeOffers[offer.ID] = &offer
+	return &offer, nil
+}
+
+// checkTrade validates that an offer can be executed right now without changing anything.
+// Returns both parties on success.
+func checkTrade(offer *TradeOffer) (from, to *Player, err error) {
+	from, to = Players[offer.FromID], Players[offer.ToID]
+	if from == nil || to == nil {
+		return nil, nil, ErrPlayerNotFound
+	}
+	if from == to {
+		return nil, nil, ErrSelfTrade
+	}
+	src, dst := from.ActiveShip(), to.ActiveShip()
+	if src.LocationKey != dst.LocationKey {
+		return nil, nil, ErrNotDockedTogether
+	}
+
+	// 1. Credits (the recipient also posts collateral for the contracts they take over)
+	if from.Credits < offer.Credits {
+		return nil, nil, ErrInsufficientCredits
+	}
+	collateral := 0
+	for _, id := range offer.Contracts {
+		idx := findContract(src.ActiveContracts, id)
+		if idx == -1 {
+			return nil, nil, ErrContractNotFound
+		}
+		c := src.ActiveContracts[idx]
+		if c.ContractorID != "" {
+			return nil, nil, ErrNotTransferable
+		}
+		if c.PostedBy == to.ID {
+			return nil, nil, ErrOwnPosting // Defined in postings.go
+		}
+		collateral += c.Collateral
+	}
+	if to.Credits+offer.Credits < offer.RequestCredits+collateral {
+		return nil, nil, ErrInsufficientCredits
+	}
+
+	// 2. Cargo: Load the contracts onto the recipient's ship one by one, then undo
+	loaded := dst.ActiveContracts
+	defer func() { dst.ActiveContracts = loaded }()
+	for _, id := range offer.Contracts {
+		c := src.ActiveContracts[findContract(src.ActiveContracts, id)]
+		if err := canLoad(dst, c); err != nil {
+			return nil, nil, err
+		}
+		if err := checkStanding(to, c); err != nil {
+			return nil, nil, err
+		}
+		dst.ActiveContracts = append(dst.ActiveContracts, c)
+	}
+
+	// 3. Modules: Every listed module must be in storage (duplicates need as many copies)
+	stored := src.StoredModules
+	for _, key := range offer.Modules {
+		idx := findModule(stored, key)
+		if idx == -1 {
+			return nil, nil, ErrModuleNotStored
+		}
+		stored = removeModule(stored, idx)
+	}
+
+	// 4. Transfer limits: Both sides may only send what their allowance covers
+	if value := tradeValue(offer, src); value > 0 {
+		if err := checkOutgoing(from, value); err != nil {
+			return nil, nil, err
+		}
+	}
+	if offer.RequestCredits > 0 {
+		if err := checkOutgoing(to, offer.RequestCredits); err != nil {
+			return nil, nil, err
+		}
+	}
+	return from, to, nil
+}
+
+// tradeValue is what the offering side sends: the credits plus the offered modules at resale value.
+func tradeValue(offer *TradeOffer, src *Ship) int {
+	value := offer.Credits
+	stored := src.StoredModules
+	for _, key := range offer.Modules {
+		if idx := findModule(stored, key); idx != -1 {
+			value += ModuleResaleValue(stored[idx])
+			stored = removeModule(stored, idx)
+		}
+	}
+	return value
+}
+
+// checkOutgoing reports whether a player may send 'amount' worth of value to other players.
+func checkOutgoing(p *Player, amount int) error {
+	if p.Debt > 0 {
+		return ErrInDebt // Defined in bank.go
+	}
+	balance := CurrentUniverse.BalanceConfig
+	if p.LifetimeEarnings < balance.TransferMinEarnings {
+		return ErrTransfersLocked
+	}
+	if balance.TransferDailyCap > 0 && transferredToday(p)+amount > balance.TransferDailyCap {
+		return ErrTransferCap
+	}
+	return nil
+}
+
+// recordOutgoing counts value sent to other players against today's allowance.
+func recordOutgoing(p *Player, amount int) {
+	p.TransferredToday = transferredToday(p) + amount
+	p.TransferDay = time.Now().Unix() / 86400
+}
+
+// transferredToday returns the value the player has sent to others since midnight (UTC).
+func transferredToday(p *Player) int {
+	if p.TransferDay != time.Now().Unix()/86400 {
+		return 0
+	}
+	return p.TransferredToday
+}
+
+// AcceptTrade executes a pending offer addressed to the player.
+// All checks run before the first item moves, so the trade is all-or-nothing.
+// Note: Caller must hold DataLock
+func AcceptTrade(p *Player, offerID string) (*TradeOffer, error) {
+	offer := TradeOffers[offerID]
+	if offer == nil || offer.ToID != p.ID {
+		return nil, ErrOfferNotFound
+	}
+	from, to, err := checkTrade(offer)
+	if err != nil {
+		return nil, err
+	}
+	src, dst := from.ActiveShip(), to.ActiveShip()
+	if value := tradeValue(offer, src); value > 0 {
+		recordOutgoing(from, value)
+	}
+	if offer.RequestCredits > 0 {
+		recordOutgoing(to, offer.RequestCredits)
+	}
+
+	// 1. Credits
+	if offer.Credits > 0 {
+		from.Credits -= offer.Credits
+		to.Credits += offer.Credits
+		recordLedger(from, LedgerTradeOut, -offer.Credits, offer.ID, fmt.Sprintf("Trade with %s", to.ID))
+		recordLedger(to, LedgerTradeIn, offer.Credits, offer.ID, fmt.Sprintf("Trade with %s", from.ID))
+	}
+	if offer.RequestCredits > 0 {
+		to.Credits -= offer.RequestCredits
+		from.Credits += offer.RequestCredits
+		recordLedger(to, LedgerTradeOut, -offer.RequestCredits, offer.ID, fmt.Sprintf("Trade with %s", from.ID))
+		recordLedger(from, LedgerTradeIn, offer.RequestCredits, offer.ID, fmt.Sprintf("Trade with %s", to.ID))
+	}
+
+	// 2. Contracts: The collateral passes from the contractor to the subcontractor
+	for _, id := range offer.Contracts {
+		idx := findContract(src.ActiveContracts, id)
+		c := src.ActiveContracts[idx]
+		src.ActiveContracts = append(src.ActiveContracts[:idx], src.ActiveContracts[idx+1:]...)
+
+		releaseCollateral(from, c)
+		c.ContractorID = from.ID
+		c.ContractorShare = offer.Share
+		deadline := c.Deadline
+		holdCollateral(to, &c) // Affordability was checked by checkTrade
+		c.Deadline = deadline  // The clock keeps running for the subcontractor
+		dst.ActiveContracts = append(dst.ActiveContracts, c)
+	}
+
+	// 3. Modules
+	for _, key := range offer.Modules {
+		idx := findModule(src.StoredModules, key)
+		dst.StoredModules = append(dst.StoredModules, src.StoredModules[idx])
+		src.StoredModules = removeModule(src.StoredModules, idx)
+	}
+
+	delete(TradeOffers, offer.ID)
+	return offer, nil
+}
+
+// DeclineTrade removes a pending offer. The recipient declines it, or the sender withdraws it.
+// Note: Caller must hold DataLock
+func DeclineTrade(p *Player, offerID string) error {
+	offer := TradeOffers[offerID]
+	if offer == nil || (offer.ToID != p.ID && offer.FromID != p.ID) {
+		return ErrOfferNotFound
+	}
+	delete(TradeOffers, offer.ID)
+	return nil
+}
+
+// PendingTrades returns the offers a player has received and sent.
+// Note: Caller must hold DataLock
+func PendingTrades(p *Player) (incoming, outgoing []TradeOffer) {
+	incoming, outgoing = []TradeOffer{}, []TradeOffer{}
+	for _, o := range TradeOffers {
+		switch p.ID {
+		case o.ToID:
+			incoming = append(incoming, *o)
+		case o.FromID:
+			outgoing = append(outgoing, *o)
+		}
+	}
+	return incoming, outgoing
+}
+
+// payContractor hands the contractor's share of a subcontracted delivery over.
+// Returns the amount paid; the deliverer keeps the rest.
+func payContractor(deliverer *Player, c Contract) int {
+	contractor := Players[c.ContractorID]
+	if contractor == nil || c.ContractorShare <= 0 {
+		return 0
+	}
+	cut := c.Payout * c.ContractorShare / 100
+	if cut <= 0 {
+		return 0
+	}
+
+	deliverer.Credits -= cut
+	contractor.Credits += cut
+	contractor.LifetimeEarnings += cut
+	recordLedger(deliverer, LedgerSubcontract, -cut, c.ID, fmt.Sprintf("%d%% share to contractor %s", c.ContractorShare, contractor.ID))
+	recordLedger(contractor, LedgerSubcontract, cut, c.ID, fmt.Sprintf("%d%% share of delivery by %s", c.ContractorShare, deliverer.ID))
+	return cut
+}
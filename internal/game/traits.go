@@ -0,0 +1,205 @@
+/*
+Package game
+File: traits.go
+Description:
+    Implements the special rules of commodity traits.
+
+    Commodities list their traits in 'universe.yaml'; the rules are tuned in
+    the 'cargo_traits' section:
+    - hazardous:  Requires a containment module to accept, raises burn while aboard.
+    - perishable: Loses payout with every LY flown.
+    - fragile:    May be destroyed on any flight.
+    - contraband: Only loaded at its pickup planets; customs may seize it on arrival.
+
+    AcceptContract and TravelShip (travel.go) call into this file, so the rules
+    apply to players, automated routes and NPC traders alike.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Commodity trait keys.
+const (
+	TraitHazardous  = "hazardous"
+	TraitPerishable = "perishable"
+	TraitFragile    = "fragile"
+	TraitContraband = "contraband"
+)
+
+// Cargo rule errors.
+var (
+	ErrContainmentRequired = errors.New("hazardous cargo requires a containment module")
+	ErrContrabandOrigin    = errors.New("contraband cannot be loaded at this planet")
+)
+
+// HasTrait reports whether a trait is in the list.
+func HasTrait(traits []string, trait string) bool {
+	for _, t := range traits {
+		if t == trait {
+			return true
+		}
+	}
+	return false
+}
+
+// CanPickup reports whether a commodity may be loaded at a planet.
+// Contraband is restricted to its pickup planets; everything else loads anywhere.
+func CanPickup(comm Commodity, planetKey string) bool {
+	if !HasTrait(comm.Traits, TraitContraband) || len(comm.PickupPlanets) == 0 {
+		return true
+	}
+	for _, k := range comm.PickupPlanets {
+		if k == planetKey {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCargoRules validates the trait rules for loading a contract onto a ship.
+func checkCargoRules(ship *Ship, c Contract) error {
+	if HasTrait(c.Traits, TraitHazardous) && !hasContainment(ship.InstalledModules) {
+		return ErrContainmentRequired
+	}
+	if HasTrait(c.Traits, TraitContraband) {
+		if comm := GetCommodity(c.ItemKey); comm != nil && !CanPickup(*comm, ship.LocationKey) {
+			return ErrContrabandOrigin
+		}
+	}
+	return nil
+}
+
+// hasContainment reports whether the modules include the containment module (or a higher tier of it).
+func hasContainment(modules []ShipModule) bool {
+	module := CurrentUniverse.CargoTraits.Hazardous.ContainmentModule
+	return module == "" || hasModuleOrUpgrade(modules, module)
+}
+
+// carriesHazardous reports whether any contract aboard the ship is hazardous.
+func carriesHazardous(ship *Ship) bool {
+	for _, c := range ship.ActiveContracts {
+		if HasTrait(c.Traits, TraitHazardous) {
+			return true
+		}
+	}
+	return false
+}
+
+// HazardBurnMult returns the burn multiplier caused by hazardous cargo on board.
+func HazardBurnMult(ship *Ship) float64 {
+	mult := CurrentUniverse.CargoTraits.Hazardous.BurnMult
+	if mult <= 0 {
+		return 1.0
+	}
+	if carriesHazardous(ship) {
+		return mult
+	}
+	return 1.0
+}
+
+// DeliveryValue returns the payout a contract is worth at delivery.
+// Perishable goods lose DecayPerLY of their value for every LY flown, down to MinValue.
+func DeliveryValue(c Contract) int {
+	if !HasTrait(c.Traits, TraitPerishable) {
+		return c.Payout
+	}
+	cfg := CurrentUniverse.CargoTraits.Perishable
+	factor := math.Max(cfg.MinValue, 1.0-cfg.DecayPerLY*float64(c.Travelled))
+	if factor > 1.0 {
+		factor = 1.0
+	}
+	return int(float64(c.Payout) * factor)
+}
+
+// applyFlightRisks runs the in-flight trait rules after the ship arrived at 'destKey'.
+// Ages perishable goods, rolls fragile losses and customs inspections. Lost and
+// seized contracts are removed from the hold and recorded in the result.
+func applyFlightRisks(p *Player, ship *Ship, destKey string, dist int64, result *TravelResult) {
+	traits := CurrentUniverse.CargoTraits
+	inspected := HasService(destKey, ServiceCustoms) && rand.Float64() < traits.Contraband.InspectionChance
+
+	remaining := []Contract{}
+	for _, c := range ship.ActiveContracts {
+		c.Travelled += dist
+
+		switch {
+		case HasTrait(c.Traits, TraitFragile) && rand.Float64() < traits.Fragile.LossChance:
+			result.Lost = append(result.Lost, c)
+			p.Reputation -= ReputationPerDrop
+			penalizeStanding(p, c)                               // Defined in factions.go
+			forfeitCollateral(p, c, "cargo destroyed in flight") // Defined in escrow.go
+			if c.PostedBy != "" {
+				failPosting(c, "cargo destroyed in flight") // Defined in postings.go
+			}
+		case inspected && HasTrait(c.Traits, TraitContraband):
+			result.Confiscated = append(result.Confiscated, c)
+			forfeitCollateral(p, c, "cargo seized by customs")
+			if c.PostedBy != "" {
+				failPosting(c, "cargo seized by customs")
+			}
+			result.Fine += int(float64(c.Payout) * traits.Contraband.FineMult)
+		default:
+			remaining = append(remaining, c)
+		}
+	}
+	ship.ActiveContracts = remaining
+
+	// Customs take what they can; the fine never pushes the wallet below zero
+	result.Fine = min(result.Fine, max(p.Credits, 0))
+	if result.Fine > 0 {
+		p.Credits -= result.Fine
+		recordLedger(p, LedgerFine, -result.Fine, destKey, fmt.Sprintf("Customs fine for %d seized contracts", len(result.Confiscated)))
+	}
+}
+
+// ValidateTraits checks commodity traits and the 'cargo_traits' tuning.
+// The containment module must be a valid module, so this runs after ValidateModules.
+func ValidateTraits(u *Universe) error {
+	known := map[string]bool{TraitHazardous: true, TraitPerishable: true, TraitFragile: true, TraitContraband: true}
+	planets := make(map[string]bool)
+	for _, p := range u.Planets {
+		planets[p.Key] = true
+	}
+
+	for _, c := range u.Commodities {
+		for _, t := range c.Traits {
+			if !known[t] {
+				return fmt.Errorf("commodity %q: unknown trait %q", c.Key, t)
+			}
+		}
+		for _, k := range c.PickupPlanets {
+			if !planets[k] {
+				return fmt.Errorf("commodity %q: unknown pickup planet %q", c.Key, k)
+			}
+		}
+	}
+
+	cfg := u.CargoTraits
+	if m := cfg.Hazardous.ContainmentModule; m != "" {
+		found := false
+		for _, mod := range u.ShipModules {
+			found = found || mod.Key == m
+		}
+		if !found {
+			return fmt.Errorf("cargo_traits: unknown containment module %q", m)
+		}
+	}
+	for name, chance := range map[string]float64{
+		"fragile.loss_chance":          cfg.Fragile.LossChance,
+		"contraband.inspection_chance": cfg.Contraband.InspectionChance,
+	} {
+		if chance < 0 || chance > 1 {
+			return fmt.Errorf("cargo_traits: %s must be between 0 and 1", name)
+		}
+	}
+	if cfg.Perishable.DecayPerLY < 0 || cfg.Perishable.MinValue < 0 || cfg.Contraband.FineMult < 0 {
+		return errors.New("cargo_traits: values must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Package game
+File: travel.go
+Description:
+    The ship operations shared by players and automated routes.
+    This includes:
+    1. Taking contracts from the local job board (capacity rules).
+    2. Flying between planets (fuel burn and deliveries).
+    3. Paying for fuel at a depot.
+
+    The API handlers and the route runner (routes.go) both go through these
+    functions, so a scripted ship can never bend rules a pilot has to follow.
+*/
+
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Operation errors.
+var (
+	ErrContractNotFound   = errors.New("contract not found")
+	ErrCargoFull          = errors.New("insufficient cargo space")
+	ErrPassengersFull     = errors.New("insufficient passenger slots")
+	ErrComfortTooLow      = errors.New("ship comfort rating too low")
+	ErrDestinationInvalid = errors.New("destination invalid")
+	ErrInsufficientFuel   = errors.New("insufficient fuel for current mass")
+)
+
+// AcceptContract moves a contract from the job board at the ship's location into its hold.
+// Triggers Market Scarcity (Source Heat).
+// Note: Caller must hold DataLock
+func AcceptContract(p *Player, ship *Ship, contractID string) (Contract, error) {
+	location := ship.LocationKey
+	board := AvailableContracts[location]
+
+	// 1. Find the contract (offers reserved for someone else are invisible)
+	foundIdx := findContract(board, contractID)
+	if foundIdx == -1 || !board[foundIdx].AvailableTo(p) {
+		return Contract{}, ErrContractNotFound
+	}
+	// Posters take their goods back with WithdrawPosting (postings.go)
+	if board[foundIdx].PostedBy == p.ID {
+		return Contract{}, ErrOwnPosting
+	}
+	// Faction-only jobs need standing; the payout follows the standing (factions.go)
+	if err := checkStanding(p, board[foundIdx]); err != nil {
+		return Contract{}, err
+	}
+	target := PriceForPlayer(p, board[foundIdx])
+
+	// 2. Validate Ship Capacity
+	if err := canLoad(ship, target); err != nil {
+		return Contract{}, err
+	}
+
+	// Valuable cargo: the deposit goes into escrow (escrow.go)
+	if err := holdCollateral(p, &target); err != nil {
+		return Contract{}, err
+	}
+
+	// 3. Transfer Contract
+	ship.ActiveContracts = append(ship.ActiveContracts, target)
+	AvailableContracts[location] = append(board[:foundIdx], board[foundIdx+1:]...)
+
+	// 4. Update Market Economy
+	// Accepting a contract makes the good scarcer at the origin.
+	Market.RecordAcceptance(target.OriginKey, target.ItemKey, target.Quantity)
+	recordStats(p, func(s *PlayerStats) { s.Accepted++ }) // Defined in stats.go
+
+	return target, nil
+}
+
+// findContract returns the index of a contract in a list, or -1.
+func findContract(list []Contract, contractID string) int {
+	for i, c := range list {
+		if c.ID == contractID {
+			return i
+		}
+	}
+	return -1
+}
+
+// canLoad checks capacity, comfort and cargo trait rules for taking a contract aboard.
+func canLoad(ship *Ship, target Contract) error {
+	// We must count currently loaded items to ensure we don't overfill.
+	currentCargo, currentPass := CalculateLoad(ship)
+	if target.Type == "cargo" && currentCargo+target.Quantity > ship.Effective.CargoCapacity {
+		return ErrCargoFull
+	}
+	if target.Type == "passenger" && currentPass+target.Quantity > ship.Effective.PassengerSlots {
+		return ErrPassengersFull
+	}
+	if target.ComfortRequired > ship.Effective.Comfort {
+		return ErrComfortTooLow
+	}
+	return checkCargoRules(ship, target) // Defined in traits.go
+}
+
+// TravelShip flies the ship to another planet and delivers every contract bound there.
+// Payouts, reputation and customs fines go to the owning player.
+// Note: Caller must hold DataLock
+func TravelShip(p *Player, ship *Ship, destKey string) (TravelResult, error) {
+	dest := GetPlanet(destKey)
+	current := GetPlanet(ship.LocationKey)
+	if dest == nil || current == nil {
+		return TravelResult{}, ErrDestinationInvalid
+	}
+
+	// 1. Calculate Costs (Physics)
+	dist := CalculateDistance(current.Coordinates, dest.Coordinates)
+	fuelNeeded := dist * CalculateRouteBurn(ship, ship.Fuel, dest.Key) // Storms on the route burn more
+	if ship.Fuel < fuelNeeded {
+		return TravelResult{}, ErrInsufficientFuel
+	}
+
+	// 2. Move Ship
+	ship.Fuel -= fuelNeeded
+	ship.LocationKey = dest.Key
+	recordStats(p, func(s *PlayerStats) {
+		s.Distance += dist
+		s.FuelBurned += fuelNeeded
+		s.Trips++
+	})
+
+	// 3. In-Flight Risks (perishable decay, fragile losses, customs)
+	result := TravelResult{Distance: dist, FuelUsed: fuelNeeded, Delivered: []Contract{}}
+	applyFlightRisks(p, ship, dest.Key, dist, &result) // Defined in traits.go
+
+	// 4. Process Deliveries
+	remaining := []Contract{}
+	for _, c := range ship.ActiveContracts {
+		if c.DestinationKey != dest.Key {
+			remaining = append(remaining, c) // Contract stays on board
+			continue
+		}
+
+		// Multi-stop contracts drop their share here and fly on (contracts.go)
+		if len(c.Stops) > 1 {
+			drop, rest := deliverStop(c)
+			payDelivery(p, drop, &result)
+			Market.RecordDelivery(dest.Key, drop.ItemKey, drop.Quantity)
+			remaining = append(remaining, rest)
+			continue
+		}
+
+		escrowed := c.Payout
+		c.Payout = DeliveryValue(c)
+		payDelivery(p, c, &result)
+		if c.PostedBy != "" {
+			refundPostingDecay(c, escrowed) // Defined in postings.go
+		}
+		p.Reputation += ReputationPerDelivery + c.BonusReputation
+		rewardStanding(p, c) // Defined in factions.go
+
+		// Economy Update: Flooding the market at destination
+		Market.RecordDelivery(c.DestinationKey, c.ItemKey, c.Quantity)
+
+		// Chained contracts unlock the next job at the destination
+		if offer := offerFollowUp(p, c); offer != nil {
+			result.FollowUps = append(result.FollowUps, *offer)
+		}
+	}
+
+	ship.ActiveContracts = remaining
+	p.LifetimeEarnings += result.Payout
+	result.CorpTax = collectCorpTax(p, &result) // Defined in corporations.go
+	return result, nil
+}
+
+// payDelivery credits the payout (and refunds the collateral) of a delivered contract
+// and records it in the trip result. Subcontracted jobs pay the contractor's share first.
+func payDelivery(p *Player, c Contract, result *TravelResult) {
+	p.Credits += c.Payout
+	recordLedger(p, LedgerDelivery, c.Payout, c.ID, fmt.Sprintf("Delivered %d %s to %s", c.Quantity, c.ItemName, c.DestinationKey))
+	cut := payContractor(p, c)                 // Defined in trade.go
+	result.Refunded += releaseCollateral(p, c) // Defined in escrow.go
+	if c.PostedBy != "" {
+		settlePosting(c) // Defined in postings.go
+	}
+
+	result.Payout += c.Payout - cut
+	result.Delivered = append(result.Delivered, c)
+	recordStats(p, func(s *PlayerStats) { s.addDelivery(c, c.Payout-cut) })
+}
+
+// BuyFuel executes a quote from PlanRefuel: charges the player and fills the ship from the local depot.
+// Note: Caller must hold DataLock
+func BuyFuel(p *Player, ship *Ship, quote RefuelQuote) error {
+	if !quote.CanAfford {
+		return ErrInsufficientCredits
+	}
+	p.Credits -= quote.Cost
+	recordLedger(p, LedgerFuel, -quote.Cost, ship.LocationKey, fmt.Sprintf("Bought %.2f fuel for %s", float64(quote.Amount)/FuelUnitScale, ship.Name))
+	ship.Fuel = quote.FuelAfter
+	Market.FuelStock[ship.LocationKey] -= quote.Amount
+	return nil
+}
@@ -67,9 +67,46 @@ func main() {
 	// Every 60 seconds, it triggers the simulation to:
 	// a) Recover market prices (Cool down heat maps).
 	// b) Generate new contracts if planets are running low.
+	// c) Advance the automated routes of secondary ships by one step.
+	// d) Let NPC traders take and deliver contracts.
+	// e) Start and end galactic events (broadcast to all clients).
+	// f) Expire overdue contracts (collateral is forfeited).
+	// g) Add interest to player debt.
+	// h) Broadcast the leaderboards every few ticks.
 	go func() {
 		ticker := time.NewTicker(60 * time.Second)
+		ticks := 0
 		for range ticker.C {
+			ticks++
+			// Automated ships act first so they compete for the current boards.
+			if steps := game.RunRoutes(); steps > 0 {
+				log.Printf("HEARTBEAT: Executed %d route steps", steps)
+			}
+			if delivered := game.RunNPCTraders(); delivered > 0 {
+				log.Printf("HEARTBEAT: NPC traders delivered %d contracts", delivered)
+			}
+			if expired := game.ExpireContracts(); expired > 0 {
+				log.Printf("HEARTBEAT: %d contracts expired", expired)
+			}
+			if debtors := game.AccrueInterest(); debtors > 0 {
+				log.Printf("HEARTBEAT: Interest charged to %d players", debtors)
+			}
+
+			// Events change before the boards refill, so new contracts reflect them.
+			started, ended := game.TickEvents()
+			for _, e := range ended {
+				gameHub.Publish("event_ended", e)
+				log.Printf("EVENT: %s at %s has ended", e.Name, e.PlanetKey)
+			}
+			for _, e := range started {
+				gameHub.Publish("event_started", e)
+				log.Printf("EVENT: %s struck %s for %d ticks", e.Name, e.PlanetKey, e.Duration)
+			}
+
+			if ticks%game.LeaderboardPulseTicks == 0 {
+				gameHub.Publish("leaderboard", game.LeaderboardPulse())
+			}
+
 			// Run the simulation logic (Thread-safe inside the game package).
 			// Returns a list of planet IDs that received new jobs.
 			updatedPlanets := game.ReplenishMarket()
@@ -130,18 +167,76 @@ func main() {
 	mux := http.NewServeMux()
 
 	// -- Information Endpoints (Read-Only) --
-	mux.HandleFunc("/api/ship", api.HandleGetShip)           // Get player status
-	mux.HandleFunc("/api/planets", api.HandleGetPlanets)     // Get static map data
-	mux.HandleFunc("/api/contracts", api.HandleGetContracts) // Get jobs at current location
-	mux.HandleFunc("/api/modules", api.HandleGetModules)     // Get upgrades (only at Prime)
+	mux.HandleFunc("/api/ship", api.HandleGetShip)                     // Get player status
+	mux.HandleFunc("/api/planets", api.HandleGetPlanets)               // Get static map data
+	mux.HandleFunc("/api/contracts", api.HandleGetContracts)           // Get jobs at current location
+	mux.HandleFunc("/api/modules", api.HandleGetModules)               // Get upgrades (only at shipyards)
+	mux.HandleFunc("/api/market/history", api.HandleGetMarketHistory)  // Get heat/price time-series
+	mux.HandleFunc("/api/fuel", api.HandleGetFuel)                     // Get fuel prices/stock per planet
+	mux.HandleFunc("/api/hulls", api.HandleGetHulls)                   // Get ships for sale (only at shipyards)
+	mux.HandleFunc("/api/fleet/report", api.HandleGetFleetReport)      // Get profit per ship (automated routes)
+	mux.HandleFunc("/api/fleet/log", api.HandleGetRouteLog)            // Get route events of one ship
+	mux.HandleFunc("/api/events", api.HandleGetEvents)                 // Get active galactic events
+	mux.HandleFunc("/api/traders", api.HandleGetTraders)               // Get NPC traders (location, cargo, stats)
+	mux.HandleFunc("/api/ledger", api.HandleGetLedger)                 // Get credit history (filters, pagination)
+	mux.HandleFunc("/api/ledger/reconcile", api.HandleReconcileLedger) // Check the ledger against the wallet
+	mux.HandleFunc("/api/bank", api.HandleGetBank)                     // Get debt, loan limit and rescue options
+	mux.HandleFunc("/api/trade/offers", api.HandleGetTradeOffers)      // Get received and sent trade offers
+	mux.HandleFunc("/api/factions", api.HandleGetFactions)             // Get factions, territory and standing
+	mux.HandleFunc("/api/corps", api.HandleListCorps)                  // List all corporations
+	mux.HandleFunc("/api/corp", api.HandleGetCorp)                     // Get own corporation (members, stats, chat)
+	mux.HandleFunc("/api/stats", api.HandleGetStats)                   // Get own statistics (time windows) and net worth
+	mux.HandleFunc("/api/leaderboard", api.HandleGetLeaderboard)       // Get players ranked by a metric
+	mux.HandleFunc("/api/achievements", api.HandleGetAchievements)     // Get achievements, unlocks and cosmetics
 
 	// -- Action Endpoints (State-Changing) --
-	mux.HandleFunc("/api/contracts/accept", api.HandleAcceptContract) // Take a job
-	mux.HandleFunc("/api/contracts/drop", api.HandleDropContract)     // Abandon a job
-	mux.HandleFunc("/api/travel", api.HandleTravel)                   // Move ship (burn fuel)
-	mux.HandleFunc("/api/travel/quote", api.HandleTravelQuote)        // Calculate fuel cost (pre-flight)
-	mux.HandleFunc("/api/refuel", api.HandleRefuel)                   // Buy fuel
-	mux.HandleFunc("/api/modules/buy", api.HandleBuyModule)           // Buy upgrade
+	mux.HandleFunc("/api/register", api.HandleRegister) // Create the player (starting ship and credits)
+	mux.HandleFunc("/api/contracts/accept", func(w http.ResponseWriter, r *http.Request) {
+		api.HandleAcceptContract(gameHub, w, r) // Take a job (unlocks are pushed over the WebSocket)
+	})
+	mux.HandleFunc("/api/contracts/drop", api.HandleDropContract)        // Abandon a job
+	mux.HandleFunc("/api/contracts/merge", api.HandleMergeCargo)         // Combine identical cargo in the hold
+	mux.HandleFunc("/api/contracts/post", api.HandlePostContract)        // Put cargo from the hold on the board
+	mux.HandleFunc("/api/contracts/withdraw", api.HandleWithdrawPosting) // Take an unaccepted posting back
+	mux.HandleFunc("/api/travel", func(w http.ResponseWriter, r *http.Request) {
+		api.HandleTravel(gameHub, w, r) // Move ship (burn fuel)
+	})
+	mux.HandleFunc("/api/travel/quote", api.HandleTravelQuote) // Calculate fuel cost (pre-flight)
+	mux.HandleFunc("/api/refuel", api.HandleRefuel)            // Buy fuel (full tank, amount or budget)
+	mux.HandleFunc("/api/refuel/quote", api.HandleRefuelQuote) // Price a refuel (pre-purchase)
+	mux.HandleFunc("/api/modules/buy", func(w http.ResponseWriter, r *http.Request) {
+		api.HandleBuyModule(gameHub, w, r) // Buy upgrade
+	})
+	mux.HandleFunc("/api/modules/uninstall", api.HandleUninstallModule) // Move upgrade to storage
+	mux.HandleFunc("/api/modules/install", api.HandleInstallModule)     // Re-install stored upgrade
+	mux.HandleFunc("/api/modules/sell", api.HandleSellModule)           // Sell upgrade for resale value
+	mux.HandleFunc("/api/hulls/buy", api.HandleBuyHull)                 // Buy an additional ship
+	mux.HandleFunc("/api/fleet/switch", api.HandleSwitchShip)           // Change the active ship
+	mux.HandleFunc("/api/fleet/route", api.HandleAssignRoute)           // Give a secondary ship orders
+	mux.HandleFunc("/api/fleet/route/cancel", api.HandleCancelRoute)    // Stop automated orders
+	mux.HandleFunc("/api/bank/loan", api.HandleTakeLoan)                // Borrow credits (only at banks)
+	mux.HandleFunc("/api/bank/repay", api.HandleRepayLoan)              // Pay back debt (only at banks)
+	mux.HandleFunc("/api/bank/bankruptcy", api.HandleBankruptcy)        // Reset an insolvent player
+	mux.HandleFunc("/api/rescue/tow", api.HandleTow)                    // Tow a stranded ship to the nearest depot
+	mux.HandleFunc("/api/trade/transfer", api.HandleTransferCredits)    // Wire credits to another player
+	mux.HandleFunc("/api/trade/offer", api.HandleOfferTrade)            // Propose a trade (same planet)
+	mux.HandleFunc("/api/trade/accept", api.HandleAcceptTrade)          // Execute a received offer
+	mux.HandleFunc("/api/trade/decline", api.HandleDeclineTrade)        // Decline or withdraw an offer
+	mux.HandleFunc("/api/corp/create", api.HandleCreateCorp)            // Found a corporation
+	mux.HandleFunc("/api/corp/join", api.HandleJoinCorp)                // Join a corporation (invite required)
+	mux.HandleFunc("/api/corp/leave", api.HandleLeaveCorp)              // Leave (the last member dissolves it)
+	mux.HandleFunc("/api/corp/role", api.HandleSetCorpRole)             // Appoint officers / hand over the lead
+	mux.HandleFunc("/api/corp/kick", api.HandleKickCorpMember)          // Remove a lower-ranked member
+	mux.HandleFunc("/api/corp/tax", api.HandleSetCorpTax)               // Set the tax on member payouts
+	mux.HandleFunc("/api/corp/deposit", api.HandleCorpDeposit)          // Pay into the treasury
+	mux.HandleFunc("/api/corp/withdraw", api.HandleCorpWithdraw)        // Take from the treasury (officers)
+	mux.HandleFunc("/api/corp/chat", func(w http.ResponseWriter, r *http.Request) {
+		api.HandleCorpChat(gameHub, w, r) // Message the other members (pushed over the WebSocket)
+	})
+	mux.HandleFunc("/api/corp/invite", func(w http.ResponseWriter, r *http.Request) {
+		api.HandleInviteCorpMember(gameHub, w, r) // Invite a player (pushed over the WebSocket)
+	})
+	mux.HandleFunc("/api/corp/revoke", api.HandleRevokeCorpInvite) // Withdraw a pending invite
 
 	// -- WebSocket Endpoint --
 	// This upgrades the HTTP connection to a persistent socket.
@@ -158,7 +253,8 @@ func main() {
 	log.Printf("Architecture: [Internal Game Logic] <-> [Internal API Layer]")
 
 	// Start listening with CORS middleware enabled.
-	if err := http.ListenAndServe(port, corsMiddleware(mux)); err != nil {
+	// PlayerMiddleware identifies the player (session token) behind every request.
+	if err := http.ListenAndServe(port, corsMiddleware(api.PlayerMiddleware(mux))); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -170,7 +266,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 		// Allow any origin for development simplicity
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
 		// Handle pre-flight OPTIONS requests
 		if r.Method == "OPTIONS" {